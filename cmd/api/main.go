@@ -2,59 +2,93 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"flag"
 	"log"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 	"web_demoservice/internal/app"
 	"web_demoservice/internal/config"
+	"web_demoservice/internal/logging"
+	"web_demoservice/internal/pii"
 	"web_demoservice/internal/telemetry"
 )
 
 func main() {
-	cfg, err := config.NewConfig("./config.toml")
+	cfg, opts, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if opts.PrintConfig {
+		if err = config.Print(os.Stdout, cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	masker, err := pii.NewMasker(cfg.PII.Rules)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Уровень логирования меняется при hot reload.
+	var logLevel slog.LevelVar
+	if err = logLevel.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+		log.Fatal(err)
+	}
+	handler, err := logging.NewHandler(os.Stderr, cfg.Log, &logLevel)
 	if err != nil {
 		log.Fatal(err)
 	}
+	// PII не должны попадать в логи даже через текст ошибок.
+	slog.SetDefault(slog.New(pii.NewLogHandler(handler, masker)))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Трейсинг добавляется первым, чтобы сброситься последним, уже после спанов остановки.
+	lc := app.NewLifecycle()
 	shutdown, err := telemetry.SetupTracing(ctx, cfg.Telemetry)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer func() {
-		ctxShutdown, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancelShutdown()
-		if err := shutdown(ctxShutdown); err != nil {
-			slog.Error("failed to shutdown tracing", slog.Any("error", err))
-		}
-	}()
+	lc.Append(app.Hook{Name: "tracing", Stop: shutdown})
 
-	api, err := app.NewApp(ctx, cfg)
+	api, err := app.NewApp(ctx, cfg, lc)
 	if err != nil {
+		// lc не запущен, поэтому трейсинг сбрасывается вручную; остальное закрыл NewApp.
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = shutdown(flushCtx)
+		flushCancel()
 		log.Fatal(err)
 	}
 
-	server := &http.Server{Addr: fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port), Handler: *api.Router}
+	reloader := app.NewReloader(api, cfg, func() (*config.Config, error) {
+		next, _, err := config.Load(os.Args[1:], os.LookupEnv)
+		return next, err
+	}, &logLevel)
+	go reloader.Run(ctx, opts.Path, 2*time.Second)
 
-	go func() {
-		if err = server.ListenAndServe(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+	if err = lc.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
 
-	slog.Info("Server started on ", slog.Any("addr", server.Addr), slog.Any("port", cfg.HTTP.Port))
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-	<-sigs
+	select {
+	case sig := <-sigs:
+		slog.Info("shutting down", slog.String("signal", sig.String()))
+	case err = <-lc.Failed():
+		slog.Error("component failed, shutting down", slog.Any("error", err))
+	}
 
-	if err = server.Shutdown(ctx); err != nil {
-		log.Fatal(err)
+	if err = lc.Stop(context.Background()); err != nil {
+		slog.Error("shutdown finished with errors", slog.Any("error", err))
+		os.Exit(1)
 	}
 }
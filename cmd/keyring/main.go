@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+	"web_demoservice/internal/infra/envelope"
+)
+
+const usage = `usage:
+  keyring init   -keyfile keys/keyring.json
+  keyring rotate -keyfile keys/keyring.json [-id <key id>]
+
+init создаёт файл с мастер-ключом и ключом blind index.
+rotate добавляет новый мастер-ключ и делает его активным; старые ключи остаются
+для чтения, пока приложение не перевернёт ими завёрнутые ключи данных.`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	keyfile := fs.String("keyfile", "keys/keyring.json", "path to keyfile")
+	id := fs.String("id", time.Now().UTC().Format("20060102T150405Z"), "id of the new master key")
+	_ = fs.Parse(os.Args[2:])
+
+	var err error
+	switch os.Args[1] {
+	case "init":
+		err = initKeyfile(*keyfile, *id)
+	case "rotate":
+		err = rotateKeyfile(*keyfile, *id)
+	default:
+		log.Fatal(usage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func initKeyfile(path, id string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("keyfile %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	masterKey, err := envelope.GenerateKey()
+	if err != nil {
+		return err
+	}
+	blindIndexKey, err := envelope.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	return save(path, envelope.Keyfile{
+		ActiveKeyID:   id,
+		MasterKeys:    map[string]string{id: masterKey},
+		BlindIndexKey: blindIndexKey,
+	})
+}
+
+func rotateKeyfile(path, id string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var kf envelope.Keyfile
+	if err = json.Unmarshal(data, &kf); err != nil {
+		return fmt.Errorf("parse keyfile %s: %w", path, err)
+	}
+	if _, ok := kf.MasterKeys[id]; ok {
+		return fmt.Errorf("key %q already exists", id)
+	}
+
+	if kf.MasterKeys[id], err = envelope.GenerateKey(); err != nil {
+		return err
+	}
+	kf.ActiveKeyID = id
+
+	return save(path, kf)
+}
+
+func save(path string, kf envelope.Keyfile) error {
+	// Проверяем, что приложение сможет загрузить файл.
+	if _, err := envelope.NewKeyring(kf); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	fmt.Printf("active key: %s\n", kf.ActiveKeyID)
+	return nil
+}
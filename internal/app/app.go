@@ -2,107 +2,565 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"sync/atomic"
 	"time"
+	"web_demoservice/internal/audit"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/breaker"
 	cache2 "web_demoservice/internal/cache"
 	"web_demoservice/internal/config"
+	"web_demoservice/internal/health"
+	"web_demoservice/internal/infra/envelope"
 	"web_demoservice/internal/infra/kafka"
 	"web_demoservice/internal/infra/postgres"
 	"web_demoservice/internal/middleware"
+	"web_demoservice/internal/pii"
+	"web_demoservice/internal/ratelimit"
 	"web_demoservice/internal/repository"
+	"web_demoservice/internal/retry"
 	"web_demoservice/internal/service"
 	"web_demoservice/internal/telemetry"
+	grpc2 "web_demoservice/internal/transport/grpc"
 	"web_demoservice/internal/transport/http/v1/handlers"
+	"web_demoservice/internal/transport/http/v1/openapi"
 	routs "web_demoservice/internal/transport/http/v1/router"
 	kafka2 "web_demoservice/internal/transport/kafka"
 
 	"github.com/gorilla/mux"
-	"github.com/rs/cors"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// Группы маршрутов для лимитов из [rate_limit.groups].
+const (
+	rateLimitGroupOrders = "orders"
+	rateLimitGroupPublic = "public"
+	rateLimitGroupAdmin  = "admin"
 )
 
 type App struct {
 	Router *http.Handler
+	// GRPCServer равен nil, если gRPC выключен в конфиге.
+	GRPCServer *grpc.Server
+
+	// Компоненты, настройки которых меняются при hot reload; limiter равен nil, если
+	// ограничение частоты выключено.
+	cache   *cache2.Cache
+	limiter *ratelimit.Limiter
+
+	// configHash — хэш действующего конфига для /debug/status, обновляется после reload.
+	configHash atomic.Value
 }
 
-func NewApp(ctx context.Context, config *config.Config) (*App, error) {
+func (a *App) setConfigHash(hash string) { a.configHash.Store(hash) }
+
+func (a *App) ConfigHash() string {
+	hash, _ := a.configHash.Load().(string)
+	return hash
+}
+
+// NewApp собирает зависимости и регистрирует в lc их запуск и остановку. Серверы и
+// консьюмер стартуют в lc.Start, фоновые задачи останавливаются в lc.Stop. Если сборка
+// не удалась, уже открытые соединения закрываются здесь: lc ещё не запущен.
+func NewApp(ctx context.Context, config *config.Config, lc *Lifecycle) (_ *App, err error) {
+	var release []func(ctx context.Context)
+	defer func() {
+		if err == nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for i := len(release) - 1; i >= 0; i-- {
+			release[i](ctx)
+		}
+	}()
+
+	app := &App{}
+	app.setConfigHash(configHash(config))
+	startedAt := time.Now()
+
 	// postgres
 	pool, err := postgres.NewPostgresPool(config.DB, ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
 	}
+	release = append(release, func(context.Context) { pool.Close() })
+	// В деградированном режиме сервис стартует без БД: заказы отдаются из кэша, консьюмер
+	// стоит на паузе, /readyz не проходит по проверке postgres.
+	dbRetry := retryPolicy(config.DB.Retry)
+	degraded := false
+	if err = retry.Do(ctx, dbRetry, "postgres", pool.Ping); err != nil {
+		if !config.DB.DegradedStart {
+			return nil, fmt.Errorf("failed to ping postgres: %w", err)
+		}
+		degraded = true
+		slog.Warn("postgres is unavailable, starting in degraded mode", slog.Any("error", err))
+	}
+	lc.Append(Hook{Name: "postgres", Stop: func(context.Context) error {
+		pool.Close()
+		return nil
+	}})
+
+	// Фоновые задачи (очистка кэша, пинги, ротация ключей, прогрев) живут до остановки.
+	ctx, stopBackground := context.WithCancel(ctx)
+	release = append(release, func(context.Context) { stopBackground() })
+	lc.Append(Hook{Name: "background", Stop: func(context.Context) error {
+		stopBackground()
+		return nil
+	}})
+
+	// PII
+	masker, err := pii.NewMasker(config.PII.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pii masker: %w", err)
+	}
 
 	// Kafka
 	consumer, err := kafka.NewConsumer(config.Kafka.Brokers, config.Kafka.GroupID, config.Kafka.Topic)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
 	}
-	dlqProducer, err := kafka.NewProducer(config.Kafka.Brokers, config.Kafka.DLQTopic)
+	release = append(release, func(ctx context.Context) { _ = consumer.Close(ctx) })
+	dlqProducer, err := kafka.NewProducer(config.Kafka.Brokers, config.Kafka.DLQTopic,
+		kafka.WithErrorRedactor(masker.Text),
+		kafka.WithValueRedactor(masker.Payload),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka dlq producer: %w", err)
 	}
+	release = append(release, func(ctx context.Context) { _ = dlqProducer.Close(ctx) })
+	// Брокер в docker compose часто поднимается позже сервиса.
+	kafkaRetry := retryPolicy(config.Kafka.Retry)
+	if err = retry.Do(ctx, kafkaRetry, "kafka_brokers", consumer.CheckBrokers); err != nil {
+		return nil, fmt.Errorf("failed to connect to kafka: %w", err)
+	}
+	if err = retry.Do(ctx, kafkaRetry, "kafka_dlq", dlqProducer.Check); err != nil {
+		return nil, fmt.Errorf("failed to connect to kafka dlq: %w", err)
+	}
 
 	// Cache
 	cache := cache2.NewCache(config.HTTP.CacheTTL)
 	cache.StartDeleting(ctx)
 	cacheObs := telemetry.WrapCache(cache)
+	telemetry.ObserveCache(cache)
 
 	// repo
-	orderRepo := repository.NewOrderPostgresRepository(pool)
+	var keyring *envelope.Keyring
+	if config.Encryption.Enabled {
+		keyring, err = envelope.LoadKeyring(config.Encryption.Keyfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load keyring: %w", err)
+		}
+	}
+	orderRepo := repository.NewOrderPostgresRepository(pool, keyring)
+	if keyring != nil {
+		startDeliveryKeyRotation(ctx, orderRepo, config.Encryption.RotationBatchSize)
+	}
 	repoObs := telemetry.WrapOrderRepository(orderRepo)
+	// Breaker снаружи телеметрии: отклонённые вызовы не считаются ошибками БД. Ping для
+	// проверок идёт мимо breaker'а, иначе открытый breaker не узнал бы о восстановлении.
+	var repoSvc service.OrderRepository = repoObs
+	var repoBreaker *breaker.Breaker
+	if config.DB.Breaker.Enabled {
+		repoBreaker = breaker.New("order_postgres", breakerSettings(config.DB.Breaker))
+		repoSvc = breaker.WrapOrderRepository(repoObs, repoBreaker)
+	}
+	customerRepo := telemetry.WrapCustomerRepository(orderRepo)
+	if repoBreaker != nil {
+		customerRepo = breaker.WrapCustomerRepository(customerRepo, repoBreaker)
+	}
 	if config.Metrics.Enabled {
 		startRepositoryPing(ctx, repoObs, config.DB.HealthCheckPeriod)
 	}
 
+	// audit
+	var (
+		auditWriter  *audit.Writer
+		auditService *service.AuditService
+	)
+	if config.Audit.Enabled {
+		auditRepo := repository.NewAuditPostgresRepository(pool)
+		auditService = service.NewAuditService(auditRepo)
+		auditWriter = audit.NewWriter(auditRepo, config.Audit.BufferSize, config.Audit.BatchSize, config.Audit.FlushInterval)
+		lc.Append(runHook("audit", 10*time.Second, auditWriter.Run))
+	}
+
 	// service
-	orderService := service.NewOrderService(repoObs, cacheObs)
+	orderService := service.NewOrderService(repoSvc, cacheObs)
 	orderServiceObs := telemetry.WrapOrderService(orderService)
-	if err = orderServiceObs.WarmUp(ctx); err != nil {
-		slog.Error("failed to warm up cache", slog.Any("error", err))
+
+	// health: /readyz не проходит, пока кэш не прогрет и зависимости недоступны.
+	warmedUp := health.NewGate("cache warm-up in progress")
+	checker := health.NewChecker(health.DefaultTimeout)
+	checker.Add("postgres", repoObs.Ping)
+	if repoBreaker != nil {
+		checker.Add("postgres_breaker", repoBreaker.Check)
 	}
+	checker.Add("kafka_brokers", consumer.CheckBrokers)
+	checker.Add("kafka_consumer_group", consumer.CheckMembership)
+	checker.Add("kafka_dlq", dlqProducer.Check)
+	checker.Add("cache_warmup", warmedUp.Check)
+
+	// Прогрев в фоне: HTTP поднимается сразу, трафик пойдёт после готовности. Неудачный
+	// прогрев не блокирует готовность — заказы читаются из БД. Если сервис стартовал без
+	// БД, прогрев ждёт её восстановления.
+	go func() {
+		if degraded {
+			if err := retry.Do(ctx, retry.Policy{Initial: dbRetry.Initial, Max: dbRetry.Max}, "postgres", repoObs.Ping); err != nil {
+				return
+			}
+		}
+		defer warmedUp.Open()
+		if err := orderServiceObs.WarmUp(ctx); err != nil {
+			slog.Error("failed to warm up cache", slog.Any("error", err))
+		}
+	}()
 
 	// handler
-	orderHandler := handlers.NewOrderHandler(orderServiceObs)
-	orderHandlerObs := handlers.NewLoggingOrderHandler(orderHandler)
-	consumerHandler := kafka2.NewOrderHandler(consumer, dlqProducer, orderServiceObs)
-	go consumerHandler.Run(ctx)
+	orderHandler := handlers.NewOrderHandler(orderServiceObs, masker)
+	var orderHandlerObs handlers.OrderHTTPHandler = handlers.NewLoggingOrderHandler(orderHandler)
+	if auditWriter != nil {
+		orderHandlerObs = handlers.NewAuditOrderHandler(orderHandlerObs, auditWriter)
+	}
+	var consumerAudit kafka2.AuditRecorder
+	if auditWriter != nil {
+		consumerAudit = auditWriter
+	}
+	consumerHandler := kafka2.NewOrderHandler(consumer, dlqProducer, orderServiceObs, consumerAudit, dbRetry)
+
+	// auth
+	var authenticator *auth.Authenticator
+	if config.Auth.Enabled {
+		authenticator, err = auth.NewAuthenticator(config.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authenticator: %w", err)
+		}
+	}
+
+	// rate limit
+	var limiter *ratelimit.Limiter
+	if config.RateLimit.Enabled {
+		store := ratelimit.NewMemoryStore()
+		store.StartCleanup(ctx, time.Minute, 10*time.Minute)
+		limiter, err = ratelimit.New(store, rateLimitGroups(config.RateLimit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rate limiter: %w", err)
+		}
+	}
+
+	// gRPC
+	var grpcServer *grpc.Server
+	if config.GRPC.Enabled {
+		grpcServer = newGRPCServer(config, orderServiceObs, authenticator, masker)
+	}
 
 	// mux register
-	router := mux.NewRouter()
-	if config.Telemetry.Enabled {
-		router.Use(otelhttp.NewMiddleware("http_server"))
+	router := newBaseRouter(config)
+	router.Handle("/livez", health.LiveHandler()).Methods(http.MethodGet)
+	router.Handle("/readyz", checker.ReadyHandler()).Methods(http.MethodGet)
+	// При отдельном admin listener метрики и /api/v1/admin на основном порту не отвечают.
+	adminBase := router
+	if config.HTTP.Admin.Enabled {
+		adminBase = newBaseRouter(config)
+		adminBase.Handle("/livez", health.LiveHandler()).Methods(http.MethodGet)
+		adminBase.Handle("/readyz", checker.ReadyHandler()).Methods(http.MethodGet)
 	}
 	if config.Metrics.Enabled {
-		router.Use(telemetry.MetricsMiddleware)
 		metricsPath := config.Metrics.Path
 		if metricsPath == "" {
 			metricsPath = "/metrics"
 		}
-		router.Handle(metricsPath, telemetry.MetricsHandler())
+		adminBase.Handle(metricsPath, telemetry.MetricsHandler())
+	}
+
+	apiMiddleware := []mux.MiddlewareFunc{middleware.MaxBody(config.HTTP.MaxBodyBytes)}
+	if config.HTTP.DevMode {
+		validator, err := openapi.NewValidator()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create openapi validator: %w", err)
+		}
+		apiMiddleware = append(apiMiddleware, validator.Middleware)
 	}
 	apiRouter := router.PathPrefix("/api/v1").Subrouter()
-	apiRouter.Use(middleware.PanicCover)
-	routs.RegisterOrderRoutes(apiRouter, orderHandlerObs)
+	apiRouter.Use(apiMiddleware...)
+	adminAPIRouter := apiRouter
+	if config.HTTP.Admin.Enabled {
+		adminAPIRouter = adminBase.PathPrefix("/api/v1").Subrouter()
+		adminAPIRouter.Use(apiMiddleware...)
+	}
+
+	publicRouter := apiRouter.NewRoute().Subrouter()
+	if limiter != nil {
+		publicRouter.Use(middleware.RateLimit(limiter, rateLimitGroupPublic, config.RateLimit.TrustForwardedFor))
+	}
+	routs.RegisterOpenAPIRoutes(publicRouter)
+
+	ordersRouter := apiRouter.NewRoute().Subrouter()
+	if config.Auth.Enabled {
+		ordersRouter.Use(middleware.Authenticate(authenticator), middleware.RequireScope(auth.ScopeOrdersRead))
+	} else {
+		slog.Warn("auth is disabled, /api/v1 is available anonymously")
+	}
+	// После аутентификации: лимит считается по клиенту, а не по IP.
+	if limiter != nil {
+		ordersRouter.Use(middleware.RateLimit(limiter, rateLimitGroupOrders, config.RateLimit.TrustForwardedFor))
+	}
+	routs.RegisterOrderRoutes(ordersRouter, orderHandlerObs)
+
+	// Админские маршруты без аутентификации не поднимаются: журнал аудита содержит, кто что читал.
+	if config.Auth.Enabled {
+		adminMiddleware := []mux.MiddlewareFunc{middleware.Authenticate(authenticator), middleware.RequireScope(auth.ScopeAdmin)}
+		if limiter != nil {
+			adminMiddleware = append(adminMiddleware, middleware.RateLimit(limiter, rateLimitGroupAdmin, config.RateLimit.TrustForwardedFor))
+		}
+		adminRouter := adminAPIRouter.NewRoute().Subrouter()
+		adminRouter.Use(adminMiddleware...)
+		// Выгрузка и удаление данных клиента без записи в аудит не выполняются.
+		if auditService != nil {
+			routs.RegisterAuditRoutes(adminRouter, handlers.NewAuditHandler(auditService, auditWriter))
+			customerService := service.NewCustomerService(customerRepo, cacheObs)
+			routs.RegisterCustomerRoutes(adminRouter, handlers.NewCustomerHandler(customerService, auditWriter))
+		} else {
+			slog.Warn("audit is disabled, customer export and erasure are not available")
+		}
+		routs.RegisterCacheRoutes(adminRouter, handlers.NewCacheHandler(cache, auditWriter))
+
+		// pprof и /debug/status раскрывают устройство процесса — только на admin listener.
+		if config.HTTP.Admin.Enabled {
+			debugRouter := adminBase.NewRoute().Subrouter()
+			debugRouter.Use(adminMiddleware...)
+			routs.RegisterDebugRoutes(debugRouter, handlers.NewDebugHandler(cache, consumer, app.ConfigHash, startedAt))
+		}
+	}
 
 	fileServer := http.FileServer(http.Dir("./web"))
 	router.PathPrefix("/").Handler(fileServer)
 
-	// Настройка CORS
-	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Accept"},
-		AllowCredentials: true,
+	// CORS только на основном listener: админку из браузера с чужого origin не открывают.
+	handler := corsHandler(config.HTTP.CORS, router)
+
+	tlsConfig, err := newTLSConfig(config.HTTP.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure tls: %w", err)
+	}
+	adminTLS, err := newTLSConfig(config.HTTP.Admin.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure admin tls: %w", err)
+	}
+
+	// Остановка идёт в обратном порядке: сначала консьюмер перестаёт читать, дорабатывает
+	// полученные записи и коммитит offset'ы, затем сбрасывается DLQ и закрываются серверы.
+	if grpcServer != nil {
+		lc.Append(grpcHook(lc, grpcServer, fmt.Sprintf("%s:%d", config.GRPC.Host, config.GRPC.Port)))
+	}
+	lc.Append(httpHook(lc, "http", newHTTPServer(config.HTTP, config.HTTP.Host, config.HTTP.Port, handler, tlsConfig), config.HTTP.ShutdownTimeout))
+	if config.HTTP.Admin.Enabled {
+		admin := config.HTTP.Admin
+		lc.Append(httpHook(lc, "http_admin", newHTTPServer(adminServerConfig(config.HTTP), admin.Host, admin.Port, adminBase, adminTLS), config.HTTP.ShutdownTimeout))
+	}
+	lc.Append(Hook{Name: "kafka_dlq", Stop: dlqProducer.Close})
+	consumerRun := runHook("kafka_consumer", 15*time.Second, func(ctx context.Context) {
+		go consumer.RunLagMonitor(ctx, config.Kafka.LagInterval)
+		consumerHandler.Run(ctx)
 	})
+	stopFetching := consumerRun.Stop
+	consumerRun.Stop = func(ctx context.Context) error {
+		if err := stopFetching(ctx); err != nil {
+			return err
+		}
+		return consumer.Close(ctx)
+	}
+	lc.Append(consumerRun)
+
+	app.Router = &handler
+	app.GRPCServer = grpcServer
+	app.cache = cache
+	app.limiter = limiter
+	return app, nil
+}
 
-	// Оборачиваем роутер в CORS middleware
-	handler := c.Handler(router)
+// configHash — обёртка над config.Hash: в NewApp имя пакета перекрыто параметром.
+func configHash(cfg *config.Config) string {
+	return config.Hash(cfg)
+}
 
-	return &App{
-		Router: &handler,
-	}, nil
+// runHook запускает run в отдельной горутине; остановка отменяет его контекст и ждёт
+// возврата.
+func runHook(name string, timeout time.Duration, run func(ctx context.Context)) Hook {
+	var (
+		cancel context.CancelFunc
+		done   = make(chan struct{})
+	)
+	return Hook{
+		Name:    name,
+		Timeout: timeout,
+		Start: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// httpHook: ошибка Serve после старта передаётся в lc.Fail. Остановка ждёт активные
+// запросы до timeout.
+func httpHook(lc *Lifecycle, name string, server *http.Server, timeout time.Duration) Hook {
+	return Hook{
+		Name:    name,
+		Timeout: timeout,
+		Start: func(context.Context) error {
+			lis, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				var err error
+				if server.TLSConfig != nil {
+					// Сертификат уже загружен в TLSConfig.
+					err = server.ServeTLS(lis, "", "")
+				} else {
+					err = server.Serve(lis)
+				}
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					lc.Fail(fmt.Errorf("%s server: %w", name, err))
+				}
+			}()
+			slog.Info("HTTP server started", slog.String("listener", name), slog.String("addr", lis.Addr().String()),
+				slog.Bool("tls", server.TLSConfig != nil))
+			return nil
+		},
+		Stop: server.Shutdown,
+	}
+}
+
+func grpcHook(lc *Lifecycle, server *grpc.Server, addr string) Hook {
+	return Hook{
+		Name: "grpc",
+		Start: func(context.Context) error {
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := server.Serve(lis); err != nil {
+					lc.Fail(fmt.Errorf("grpc server: %w", err))
+				}
+			}()
+			slog.Info("gRPC server started", slog.String("addr", lis.Addr().String()))
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			stopGRPC(ctx, server)
+			return nil
+		},
+	}
+}
+
+// stopGRPC ждёт завершения активных RPC, но не дольше дедлайна ctx: WatchOrders-стримы
+// сами по себе не заканчиваются.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// newGRPCServer: authenticator равен nil, если аутентификация выключена.
+func newGRPCServer(config *config.Config, orderService grpc2.OrderService, authenticator *auth.Authenticator, masker *pii.Masker) *grpc.Server {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	// Аутентификация идёт первой, чтобы логи видели клиента; отказы она логирует сама.
+	if authenticator != nil {
+		unary = append(unary, grpc2.AuthUnaryInterceptor(authenticator))
+		stream = append(stream, grpc2.AuthStreamInterceptor(authenticator))
+	}
+	unary = append(unary, grpc2.LoggingUnaryInterceptor)
+	stream = append(stream, grpc2.LoggingStreamInterceptor)
+	if config.Metrics.Enabled {
+		unary = append(unary, telemetry.MetricsUnaryInterceptor)
+		stream = append(stream, telemetry.MetricsStreamInterceptor)
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if config.Telemetry.Enabled {
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+
+	return grpc2.NewServer(grpc2.NewOrderServer(orderService, masker), opts...)
+}
+
+func breakerSettings(cfg config.BreakerConfig) breaker.Settings {
+	return breaker.Settings{
+		Window:         cfg.Window,
+		MinRequests:    cfg.MinRequests,
+		FailureRate:    cfg.FailureRate,
+		OpenTimeout:    cfg.OpenTimeout,
+		HalfOpenProbes: cfg.HalfOpenProbes,
+		CallTimeout:    cfg.CallTimeout,
+	}
+}
+
+func retryPolicy(cfg config.RetryConfig) retry.Policy {
+	return retry.Policy{Attempts: cfg.Attempts, Initial: cfg.InitialBackoff, Max: cfg.MaxBackoff}
+}
+
+func rateLimitGroups(cfg config.RateLimitConfig) map[string]ratelimit.Limit {
+	groups := make(map[string]ratelimit.Limit, len(cfg.Groups))
+	for name, g := range cfg.Groups {
+		groups[name] = ratelimit.Limit{Rate: g.Rate, Burst: g.Burst}
+	}
+	return groups
+}
+
+type deliveryKeyRotator interface {
+	RotateDeliveryKeys(ctx context.Context, batchSize int) (int, error)
+}
+
+// startDeliveryKeyRotation в фоне переводит доставку на активный мастер-ключ и шифрует
+// строки, записанные до включения шифрования.
+func startDeliveryKeyRotation(ctx context.Context, repo deliveryKeyRotator, batchSize int) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	go func() {
+		rotated, err := repo.RotateDeliveryKeys(ctx, batchSize)
+		if err != nil {
+			slog.Error("delivery key rotation failed", slog.Int("rotated", rotated), slog.Any("error", err))
+			return
+		}
+		if rotated > 0 {
+			slog.Info("delivery key rotation finished", slog.Int("rotated", rotated))
+		}
+	}()
 }
 
 type repositoryPinger interface {
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultHookTimeout — таймаут фазы, если у хука он не задан.
+const DefaultHookTimeout = 5 * time.Second
+
+// Hook — компонент с фазами запуска и остановки; любая из функций может быть nil.
+// Timeout ограничивает каждую фазу отдельно.
+type Hook struct {
+	Name    string
+	Start   func(ctx context.Context) error
+	Stop    func(ctx context.Context) error
+	Timeout time.Duration
+}
+
+// Lifecycle запускает хуки в порядке добавления и останавливает в обратном: то, что
+// поднято первым (БД, трейсинг), закрывается последним.
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+
+	failOnce sync.Once
+	failed   chan error
+}
+
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{failed: make(chan error, 1)}
+}
+
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// Fail сообщает о фатальной ошибке компонента после старта (например, упал HTTP-сервер).
+// Учитывается только первая ошибка.
+func (l *Lifecycle) Fail(err error) {
+	l.failOnce.Do(func() {
+		l.failed <- err
+	})
+}
+
+// Failed получает ошибку, переданную в Fail.
+func (l *Lifecycle) Failed() <-chan error {
+	return l.failed
+}
+
+// Start запускает хуки по порядку. При ошибке уже запущенные останавливаются.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := append([]Hook(nil), l.hooks...)
+	l.mu.Unlock()
+
+	for i, hook := range hooks {
+		if hook.Start != nil {
+			if err := runPhase(ctx, hook, "start", hook.Start); err != nil {
+				l.setStarted(i)
+				return errors.Join(fmt.Errorf("start %s: %w", hook.Name, err), l.Stop(context.WithoutCancel(ctx)))
+			}
+		}
+		l.setStarted(i + 1)
+	}
+	return nil
+}
+
+// Stop останавливает запущенные хуки в обратном порядке. Ошибка или таймаут фазы не
+// прерывают остановку остальных.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := append([]Hook(nil), l.hooks[:l.started]...)
+	l.started = 0
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.Stop == nil {
+			continue
+		}
+		if err := runPhase(ctx, hook, "stop", hook.Stop); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Lifecycle) setStarted(n int) {
+	l.mu.Lock()
+	l.started = n
+	l.mu.Unlock()
+}
+
+// runPhase выполняет фазу с таймаутом. Если функция не уложилась, фаза считается
+// проваленной, а функция дорабатывает в фоне.
+func runPhase(ctx context.Context, hook Hook, phase string, fn func(context.Context) error) error {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("%s timed out after %s", phase, timeout)
+	}
+
+	attrs := []any{
+		slog.String("component", hook.Name),
+		slog.String("phase", phase),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		slog.Error("lifecycle phase failed", append(attrs, slog.Any("error", err))...)
+		return err
+	}
+	slog.Info("lifecycle phase done", attrs...)
+	return nil
+}
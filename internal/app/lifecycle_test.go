@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func recordingHook(name string, calls *[]string, startErr error) Hook {
+	return Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			*calls = append(*calls, "start "+name)
+			return startErr
+		},
+		Stop: func(context.Context) error {
+			*calls = append(*calls, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestLifecycle_StopsInReverseOrder(t *testing.T) {
+	var calls []string
+	lc := NewLifecycle()
+	lc.Append(recordingHook("postgres", &calls, nil))
+	lc.Append(recordingHook("http", &calls, nil))
+	lc.Append(recordingHook("kafka_consumer", &calls, nil))
+
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := lc.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	want := []string{
+		"start postgres", "start http", "start kafka_consumer",
+		"stop kafka_consumer", "stop http", "stop postgres",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+}
+
+func TestLifecycle_StartFailureStopsStartedHooks(t *testing.T) {
+	var calls []string
+	lc := NewLifecycle()
+	lc.Append(recordingHook("postgres", &calls, nil))
+	lc.Append(recordingHook("http", &calls, errors.New("address in use")))
+	lc.Append(recordingHook("kafka_consumer", &calls, nil))
+
+	if err := lc.Start(context.Background()); err == nil {
+		t.Fatalf("expected start error")
+	}
+
+	want := []string{"start postgres", "start http", "stop postgres"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+}
+
+func TestLifecycle_StopTimeoutDoesNotBlockOthers(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	var calls []string
+	lc := NewLifecycle()
+	lc.Append(recordingHook("postgres", &calls, nil))
+	lc.Append(Hook{
+		Name:    "http",
+		Timeout: 20 * time.Millisecond,
+		Stop: func(context.Context) error {
+			<-release
+			return nil
+		},
+	})
+
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := lc.Stop(context.Background()); err == nil {
+		t.Fatalf("expected timeout error")
+	}
+	if len(calls) != 2 || calls[1] != "stop postgres" {
+		t.Fatalf("postgres must be stopped after timed out hook: %v", calls)
+	}
+}
+
+func TestLifecycle_FailKeepsFirstError(t *testing.T) {
+	lc := NewLifecycle()
+	first := errors.New("http server: closed")
+	lc.Fail(first)
+	lc.Fail(errors.New("grpc server: closed"))
+
+	if err := <-lc.Failed(); !errors.Is(err, first) {
+		t.Fatalf("expected first error, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	"web_demoservice/internal/config"
+	"web_demoservice/internal/telemetry"
+)
+
+// Поля конфига, которые применяются без перезапуска; остальные изменения только
+// попадают в лог как требующие рестарта.
+const (
+	reloadCacheTTL    = "http.cache_ttl"
+	reloadLogLevel    = "log.level"
+	reloadSampleRatio = "telemetry.sample_ratio"
+	reloadRateLimits  = "rate_limit.groups"
+)
+
+// Reloader перечитывает конфиг всеми слоями (файл, env, флаги) по SIGHUP или при
+// изменении файла и применяет безопасные настройки к работающему приложению.
+type Reloader struct {
+	app      *App
+	load     func() (*config.Config, error)
+	logLevel *slog.LevelVar
+
+	mu sync.Mutex
+	// running — действующий конфиг: перезагружаемые поля обновляются, остальные остаются
+	// стартовыми, поэтому неприменённые изменения сообщаются при каждом reload до рестарта.
+	running config.Config
+}
+
+func NewReloader(app *App, running *config.Config, load func() (*config.Config, error), logLevel *slog.LevelVar) *Reloader {
+	return &Reloader{
+		app:      app,
+		load:     load,
+		logLevel: logLevel,
+		running:  *running,
+	}
+}
+
+// Reload применяет изменения конфига; trigger попадает в лог ("sighup", "file").
+// На каждый вызов — одна запись в лог и одно увеличение config_reloads_total.
+func (r *Reloader) Reload(trigger string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		telemetry.IncConfigReload("failed")
+		slog.Error("config reload failed, keeping current config", slog.String("trigger", trigger), slog.Any("error", err))
+		return err
+	}
+
+	var applied, restart []string
+	for _, path := range config.Diff(&r.running, next) {
+		if r.apply(path, next) {
+			applied = append(applied, path)
+		} else {
+			restart = append(restart, path)
+		}
+	}
+
+	result := "applied"
+	if len(applied) == 0 {
+		result = "unchanged"
+	} else {
+		r.app.setConfigHash(configHash(&r.running))
+	}
+	telemetry.IncConfigReload(result)
+	slog.Info("config reloaded",
+		slog.String("trigger", trigger),
+		slog.Any("applied", applied),
+		slog.Any("restart_required", restart),
+	)
+	return nil
+}
+
+// apply применяет одно поле и сообщает, удалось ли это без перезапуска.
+func (r *Reloader) apply(path string, next *config.Config) bool {
+	switch path {
+	case reloadCacheTTL:
+		r.app.cache.SetTTL(next.HTTP.CacheTTL)
+		r.running.HTTP.CacheTTL = next.HTTP.CacheTTL
+	case reloadLogLevel:
+		if r.logLevel == nil {
+			return false
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(next.Log.Level)); err != nil {
+			return false
+		}
+		r.logLevel.Set(level)
+		r.running.Log.Level = next.Log.Level
+	case reloadSampleRatio:
+		telemetry.SetSampleRatio(next.Telemetry.SampleRatio)
+		r.running.Telemetry.SampleRatio = next.Telemetry.SampleRatio
+	case reloadRateLimits:
+		// Без лимитера на старте middleware не установлен — нужен рестарт.
+		if r.app.limiter == nil || !r.running.RateLimit.Enabled {
+			return false
+		}
+		if err := r.app.limiter.SetLimits(rateLimitGroups(next.RateLimit)); err != nil {
+			return false
+		}
+		r.running.RateLimit.Groups = next.RateLimit.Groups
+	default:
+		return false
+	}
+	return true
+}
+
+// Run вызывает Reload по SIGHUP и при изменении содержимого файла path (проверка раз
+// в interval) до отмены ctx.
+func (r *Reloader) Run(ctx context.Context, path string, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := fileDigest(path)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			last = fileDigest(path)
+			_ = r.Reload("sighup")
+		case <-ticker.C:
+			if digest := fileDigest(path); digest != last {
+				last = digest
+				_ = r.Reload("file")
+			}
+		}
+	}
+}
+
+// fileDigest — хэш содержимого файла: touch без изменений не вызывает reload.
+// Отсутствующий файл даёт пустой хэш.
+func fileDigest(path string) [sha256.Size]byte {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}
+	}
+	return sha256.Sum256(content)
+}
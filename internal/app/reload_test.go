@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+	cache2 "web_demoservice/internal/cache"
+	"web_demoservice/internal/config"
+	"web_demoservice/internal/ratelimit"
+)
+
+func testConfig() *config.Config {
+	cfg := config.Defaults()
+	cfg.DB.User = "user"
+	cfg.DB.Database = "db"
+	cfg.RateLimit = config.RateLimitConfig{
+		Enabled: true,
+		Groups:  map[string]config.RateLimitGroupConfig{"orders": {Rate: 1, Burst: 1}},
+	}
+	return &cfg
+}
+
+func TestReloader_AppliesRuntimeSettings(t *testing.T) {
+	current := testConfig()
+	limiter, err := ratelimit.New(ratelimit.NewMemoryStore(), rateLimitGroups(current.RateLimit))
+	if err != nil {
+		t.Fatalf("new limiter: %v", err)
+	}
+	a := &App{cache: cache2.NewCache(current.HTTP.CacheTTL), limiter: limiter}
+
+	next := testConfig()
+	next.Log.Level = "debug"
+	next.HTTP.CacheTTL = time.Minute
+	next.RateLimit.Groups = map[string]config.RateLimitGroupConfig{"orders": {Rate: 10, Burst: 5}}
+	next.DB.Host = "db.internal"
+
+	var level slog.LevelVar
+	r := NewReloader(a, current, func() (*config.Config, error) { return next, nil }, &level)
+	if err = r.Reload("test"); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	if level.Level() != slog.LevelDebug {
+		t.Fatalf("expected debug level, got %s", level.Level())
+	}
+	res, ok, err := limiter.Allow(context.Background(), "orders", "client")
+	if err != nil || !ok || res.Limit != 5 {
+		t.Fatalf("expected new burst 5, got %+v, %v, %v", res, ok, err)
+	}
+	if r.running.HTTP.CacheTTL != time.Minute {
+		t.Fatalf("expected cache ttl applied, got %s", r.running.HTTP.CacheTTL)
+	}
+	if a.ConfigHash() != config.Hash(&r.running) {
+		t.Fatalf("expected config hash of the running config after reload")
+	}
+	// Настройки, требующие рестарта, остаются стартовыми и сообщаются повторно.
+	if r.running.DB.Host == next.DB.Host {
+		t.Fatalf("restart-only setting must not be applied")
+	}
+	if r.apply("db.host", next) {
+		t.Fatalf("db.host must require restart")
+	}
+}
+
+func TestReloader_KeepsConfigOnLoadError(t *testing.T) {
+	current := testConfig()
+	a := &App{cache: cache2.NewCache(current.HTTP.CacheTTL)}
+	var level slog.LevelVar
+
+	r := NewReloader(a, current, func() (*config.Config, error) { return nil, errors.New("invalid config") }, &level)
+	if err := r.Reload("test"); err == nil {
+		t.Fatalf("expected load error")
+	}
+	if level.Level() != slog.LevelInfo || r.running.HTTP.CacheTTL != current.HTTP.CacheTTL {
+		t.Fatalf("expected running config untouched")
+	}
+}
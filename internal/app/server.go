@@ -0,0 +1,124 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"web_demoservice/internal/config"
+	"web_demoservice/internal/middleware"
+	"web_demoservice/internal/telemetry"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/cors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// newBaseRouter — роутер listener'а с трейсингом, request ID, access log и метриками для
+// всех маршрутов, включая статику и /metrics. PanicCover последний: паника становится 500,
+// который видят и лог, и метрики.
+func newBaseRouter(cfg *config.Config) *mux.Router {
+	var chain []mux.MiddlewareFunc
+	if cfg.Telemetry.Enabled {
+		chain = append(chain, otelhttp.NewMiddleware("http_server"))
+	}
+	chain = append(chain, middleware.RequestID, middleware.AccessLog(cfg.RateLimit.TrustForwardedFor))
+	if cfg.Metrics.Enabled {
+		chain = append(chain, telemetry.MetricsMiddleware)
+	}
+	chain = append(chain, middleware.PanicCover)
+
+	router := mux.NewRouter()
+	router.Use(chain...)
+	// mux не применяет Use к 404 и 405 — оборачиваем их той же цепочкой.
+	router.NotFoundHandler = wrap(http.NotFoundHandler(), chain)
+	router.MethodNotAllowedHandler = wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}), chain)
+	return router
+}
+
+func wrap(h http.Handler, chain []mux.MiddlewareFunc) http.Handler {
+	for i := len(chain) - 1; i >= 0; i-- {
+		h = chain[i](h)
+	}
+	return h
+}
+
+// newHTTPServer: tlsConfig равен nil, если TLS выключен.
+func newHTTPServer(cfg config.HTTPConfig, host string, port int, handler http.Handler, tlsConfig *tls.Config) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", host, port),
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// adminServerConfig — настройки [http] с таймаутами admin listener'а.
+func adminServerConfig(cfg config.HTTPConfig) config.HTTPConfig {
+	admin := cfg.Admin
+	cfg.ReadHeaderTimeout, cfg.ReadTimeout = admin.ReadHeaderTimeout, admin.ReadTimeout
+	cfg.WriteTimeout, cfg.IdleTimeout = admin.WriteTimeout, admin.IdleTimeout
+	return cfg
+}
+
+// newTLSConfig загружает сертификат при старте, чтобы ошибка в путях не всплыла на
+// первом соединении. Возвращает nil, если TLS выключен.
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.MinVersion == "1.3" {
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("client ca %s: no certificates found", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// corsHandler: без allowed_origins CORS-заголовки не отдаются совсем (пустой список в
+// rs/cors означал бы "*"). Учётные данные передаются заголовками, поэтому
+// allow_credentials обычно не нужен. Заголовки ответа сервиса открываются браузеру всегда.
+func corsHandler(cfg config.CORSConfig, next http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return next
+	}
+	return cors.New(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+		ExposedHeaders: []string{
+			"ETag", "Last-Modified", "Cache-Control", "WWW-Authenticate",
+			"Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset",
+			middleware.RequestIDHeader,
+		},
+	}).Handler(next)
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"web_demoservice/internal/config"
+	"web_demoservice/internal/middleware"
+)
+
+// writeSelfSigned пишет самоподписанный сертификат и ключ для localhost.
+func writeSelfSigned(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	if cfg, err := newTLSConfig(config.TLSConfig{}); cfg != nil || err != nil {
+		t.Fatalf("disabled tls must return nil, got %v, %v", cfg, err)
+	}
+
+	certFile, keyFile := writeSelfSigned(t)
+	cfg, err := newTLSConfig(config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"})
+	if err != nil {
+		t.Fatalf("new tls config: %v", err)
+	}
+	if cfg.MinVersion != tls.VersionTLS13 || cfg.ClientAuth != tls.NoClientCert {
+		t.Fatalf("unexpected tls config: min %x, client auth %v", cfg.MinVersion, cfg.ClientAuth)
+	}
+
+	// Сертификат сервера служит и CA для клиентских сертификатов.
+	cfg, err = newTLSConfig(config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile})
+	if err != nil {
+		t.Fatalf("new mtls config: %v", err)
+	}
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert || cfg.ClientCAs == nil {
+		t.Fatalf("client ca must enable mtls")
+	}
+
+	if _, err = newTLSConfig(config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile}); err == nil {
+		t.Fatalf("expected error for client ca without certificates")
+	}
+}
+
+func TestCORSHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	request := func(h http.Handler) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/order/x", nil)
+		req.Header.Set("Origin", "https://shop.example")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if got := request(corsHandler(config.CORSConfig{}, next)).Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("cors without origins must not allow anything, got %q", got)
+	}
+
+	h := corsHandler(config.CORSConfig{AllowedOrigins: []string{"https://shop.example"}, AllowedMethods: []string{"GET"}}, next)
+	if got := request(h).Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example" {
+		t.Fatalf("expected configured origin, got %q", got)
+	}
+}
+
+func TestBaseRouter_UnmatchedGoThroughMiddleware(t *testing.T) {
+	cfg := config.Defaults()
+	router := newBaseRouter(&cfg)
+	router.Handle("/livez", http.NotFoundHandler()).Methods(http.MethodGet)
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/unknown", http.StatusNotFound},
+		{http.MethodPost, "/livez", http.StatusMethodNotAllowed},
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		if rec.Code != tc.status {
+			t.Fatalf("%s %s: expected %d, got %d", tc.method, tc.path, tc.status, rec.Code)
+		}
+		if rec.Header().Get(middleware.RequestIDHeader) == "" {
+			t.Fatalf("%s %s: expected middleware chain to run", tc.method, tc.path)
+		}
+	}
+}
+
+func TestAdminServerConfig(t *testing.T) {
+	cfg := config.Defaults().HTTP
+	admin := adminServerConfig(cfg)
+	if admin.WriteTimeout != cfg.Admin.WriteTimeout || admin.ReadTimeout != cfg.Admin.ReadTimeout {
+		t.Fatalf("admin listener must use its own timeouts, got %+v", admin)
+	}
+	if cfg.WriteTimeout == admin.WriteTimeout {
+		t.Fatalf("default admin write timeout must differ from the public one")
+	}
+}
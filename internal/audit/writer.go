@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/telemetry"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultBufferSize    = 1024
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	// drainTimeout ограничивает запись остатка буфера при остановке.
+	drainTimeout = 5 * time.Second
+)
+
+// ActorAnonymous — актор запроса без аутентификации.
+const ActorAnonymous = "anonymous"
+
+type Store interface {
+	Append(ctx context.Context, events []domain.AuditEvent) error
+}
+
+// Writer асинхронно пишет события аудита пачками. Record не блокирует запрос: при
+// переполненном буфере событие отбрасывается с записью в лог и метрикой.
+type Writer struct {
+	store         Store
+	events        chan domain.AuditEvent
+	batchSize     int
+	flushInterval time.Duration
+}
+
+func NewWriter(store Store, bufferSize, batchSize int, flushInterval time.Duration) *Writer {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+
+	return &Writer{
+		store:         store,
+		events:        make(chan domain.AuditEvent, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+	}
+}
+
+// Record дополняет событие временем, клиентом и trace id из контекста и ставит в очередь.
+// У nil Writer (аудит выключен) ничего не делает.
+func (w *Writer) Record(ctx context.Context, event domain.AuditEvent) {
+	if w == nil {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	if event.Actor == "" {
+		event.Actor = ActorAnonymous
+		if identity := auth.FromContext(ctx); identity != nil {
+			event.Actor = identity.Subject
+			event.AuthMethod = string(identity.Method)
+		}
+	}
+	if event.TraceID == "" {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			event.TraceID = sc.TraceID().String()
+		}
+	}
+
+	select {
+	case w.events <- event:
+	default:
+		telemetry.AddAuditEvents("dropped", 1)
+		slog.Error("audit buffer is full, event dropped",
+			slog.String("action", event.Action), slog.String("actor", event.Actor))
+	}
+}
+
+// Run пишет события до отмены ctx, после чего сбрасывает остаток буфера.
+func (w *Writer) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.AuditEvent, 0, w.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			w.drain(batch)
+			return
+		case event := <-w.events:
+			batch = append(batch, event)
+			if len(batch) >= w.batchSize {
+				batch = w.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = w.flush(ctx, batch)
+		}
+	}
+}
+
+func (w *Writer) drain(batch []domain.AuditEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case event := <-w.events:
+			batch = append(batch, event)
+			if len(batch) >= w.batchSize {
+				batch = w.flush(ctx, batch)
+			}
+		default:
+			w.flush(ctx, batch)
+			return
+		}
+	}
+}
+
+func (w *Writer) flush(ctx context.Context, batch []domain.AuditEvent) []domain.AuditEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := w.store.Append(ctx, batch); err != nil {
+		telemetry.AddAuditEvents("failed", len(batch))
+		slog.Error("failed to write audit events", slog.Int("count", len(batch)), slog.Any("error", err))
+	} else {
+		telemetry.AddAuditEvents("written", len(batch))
+	}
+
+	return batch[:0]
+}
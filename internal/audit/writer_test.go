@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/domain"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	batches [][]domain.AuditEvent
+}
+
+func (s *memoryStore) Append(_ context.Context, events []domain.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]domain.AuditEvent(nil), events...))
+	return nil
+}
+
+func (s *memoryStore) events() []domain.AuditEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []domain.AuditEvent
+	for _, b := range s.batches {
+		out = append(out, b...)
+	}
+	return out
+}
+
+func TestWriter_RecordFillsActorFromIdentity(t *testing.T) {
+	store := &memoryStore{}
+	w := NewWriter(store, 10, 10, time.Hour)
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "frontend", Method: auth.MethodAPIKey})
+	w.Record(ctx, domain.AuditEvent{Action: domain.AuditActionOrderRead})
+	w.Record(context.Background(), domain.AuditEvent{Action: domain.AuditActionOrderRead})
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Run(runCtx)
+
+	events := store.events()
+	if len(events) != 2 {
+		t.Fatalf("expected buffer to be drained on stop, got %d events", len(events))
+	}
+	if events[0].Actor != "frontend" || events[0].AuthMethod != string(auth.MethodAPIKey) || events[0].OccurredAt.IsZero() {
+		t.Fatalf("unexpected event: %+v", events[0])
+	}
+	if events[1].Actor != ActorAnonymous {
+		t.Fatalf("expected anonymous actor, got %q", events[1].Actor)
+	}
+}
+
+func TestWriter_FlushesFullBatch(t *testing.T) {
+	store := &memoryStore{}
+	w := NewWriter(store, 10, 2, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	w.Record(ctx, domain.AuditEvent{Action: domain.AuditActionOrderRead})
+	w.Record(ctx, domain.AuditEvent{Action: domain.AuditActionOrderRead})
+
+	deadline := time.Now().Add(time.Second)
+	for len(store.events()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected full batch to be flushed without waiting for the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestWriter_DropsWhenBufferIsFull(t *testing.T) {
+	store := &memoryStore{}
+	w := NewWriter(store, 1, 10, time.Hour)
+
+	w.Record(context.Background(), domain.AuditEvent{Action: "first"})
+	w.Record(context.Background(), domain.AuditEvent{Action: "second"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Run(ctx)
+
+	if events := store.events(); len(events) != 1 || events[0].Action != "first" {
+		t.Fatalf("expected only the first event to be kept, got %+v", events)
+	}
+}
+
+func TestWriter_NilIsNoop(t *testing.T) {
+	var w *Writer
+	w.Record(context.Background(), domain.AuditEvent{Action: domain.AuditActionOrderRead})
+}
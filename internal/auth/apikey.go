@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"web_demoservice/internal/config"
+)
+
+const apiKeyHashPrefix = "sha256:"
+
+// HashAPIKey возвращает хэш ключа в формате, который ожидается в конфиге.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return apiKeyHashPrefix + hex.EncodeToString(sum[:])
+}
+
+type apiKey struct {
+	hash     [sha256.Size]byte
+	identity *Identity
+}
+
+// APIKeys проверяет статические ключи. В конфиге хранятся только sha256-хэши ключей.
+type APIKeys struct {
+	keys []apiKey
+}
+
+func NewAPIKeys(cfg []config.APIKeyConfig) (*APIKeys, error) {
+	keys := make([]apiKey, 0, len(cfg))
+	for _, k := range cfg {
+		if k.Name == "" {
+			return nil, fmt.Errorf("api key without name")
+		}
+		hexHash, ok := strings.CutPrefix(k.Hash, apiKeyHashPrefix)
+		if !ok {
+			return nil, fmt.Errorf("api key %q: hash must start with %q", k.Name, apiKeyHashPrefix)
+		}
+		raw, err := hex.DecodeString(hexHash)
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("api key %q: invalid sha256 hash", k.Name)
+		}
+
+		key := apiKey{identity: &Identity{Subject: k.Name, Method: MethodAPIKey, Scopes: k.Scopes}}
+		copy(key.hash[:], raw)
+		keys = append(keys, key)
+	}
+
+	return &APIKeys{keys: keys}, nil
+}
+
+func (a *APIKeys) Authenticate(key string) (*Identity, error) {
+	sum := sha256.Sum256([]byte(key))
+
+	// Проходим все ключи, чтобы время ответа не зависело от позиции совпадения.
+	var found *Identity
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(sum[:], k.hash[:]) == 1 {
+			found = k.identity
+		}
+	}
+	if found == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return found, nil
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"web_demoservice/internal/config"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator определяет клиента по API ключу или bearer токену.
+type Authenticator struct {
+	apiKeys *APIKeys
+	jwt     *JWTVerifier
+}
+
+func NewAuthenticator(cfg config.AuthConfig) (*Authenticator, error) {
+	apiKeys, err := NewAPIKeys(cfg.APIKeys)
+	if err != nil {
+		return nil, fmt.Errorf("api keys: %w", err)
+	}
+
+	a := &Authenticator{apiKeys: apiKeys}
+	if cfg.JWKSFile != "" {
+		a.jwt, err = NewJWTVerifier(cfg.JWKSFile, cfg.Issuer, cfg.Audience)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Authenticate принимает значения заголовков Authorization и X-API-Key.
+func (a *Authenticator) Authenticate(authorization, apiKey string) (*Identity, error) {
+	if apiKey != "" {
+		return a.apiKeys.Authenticate(apiKey)
+	}
+	if authorization == "" {
+		return nil, ErrNoCredentials
+	}
+
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, fmt.Errorf("%w: unsupported authorization scheme", ErrInvalidCredentials)
+	}
+	if a.jwt == nil {
+		return nil, fmt.Errorf("%w: bearer tokens are not configured", ErrInvalidCredentials)
+	}
+
+	return a.jwt.Verify(strings.TrimSpace(token))
+}
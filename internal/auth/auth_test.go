@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"web_demoservice/internal/config"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+func TestAPIKeys_Authenticate(t *testing.T) {
+	keys, err := NewAPIKeys([]config.APIKeyConfig{
+		{Name: "frontend", Hash: HashAPIKey("secret-1"), Scopes: []string{ScopeOrdersRead}},
+		{Name: "ops", Hash: HashAPIKey("secret-2"), Scopes: []string{ScopeAdmin}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	identity, err := keys.Authenticate("secret-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if identity.Subject != "ops" || identity.Method != MethodAPIKey {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+	if !identity.HasScope(ScopeOrdersReadPII) {
+		t.Fatalf("expected admin to have every scope")
+	}
+
+	if _, err = keys.Authenticate("unknown"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestNewAPIKeys_RejectsPlainKey(t *testing.T) {
+	_, err := NewAPIKeys([]config.APIKeyConfig{{Name: "frontend", Hash: "secret-1"}})
+	if err == nil {
+		t.Fatalf("expected error for key without sha256 hash")
+	}
+}
+
+func TestIdentity_HasScope(t *testing.T) {
+	identity := &Identity{Scopes: []string{ScopeOrdersRead}}
+	if !identity.HasScope(ScopeOrdersRead) {
+		t.Fatalf("expected orders:read")
+	}
+	if identity.HasScope(ScopeOrdersReadPII) {
+		t.Fatalf("orders:read must not imply orders:read:pii")
+	}
+
+	var anonymous *Identity
+	if anonymous.HasScope(ScopeOrdersRead) {
+		t.Fatalf("nil identity must have no scopes")
+	}
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	signer, verifier := newTestJWT(t)
+
+	token := signToken(t, signer, map[string]any{
+		"iss":   "https://issuer.test",
+		"aud":   "web_demoservice",
+		"sub":   "user-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "orders:read orders:read:pii",
+	})
+
+	identity, err := verifier.Verify(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if identity.Subject != "user-1" || identity.Method != MethodJWT {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+	if !identity.HasScope(ScopeOrdersReadPII) || identity.HasScope(ScopeAdmin) {
+		t.Fatalf("unexpected scopes: %v", identity.Scopes)
+	}
+}
+
+func TestJWTVerifier_RejectsInvalidTokens(t *testing.T) {
+	signer, verifier := newTestJWT(t)
+	otherSigner, _ := newTestJWT(t)
+
+	valid := map[string]any{
+		"iss": "https://issuer.test",
+		"aud": "web_demoservice",
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	with := func(key string, value any) map[string]any {
+		claims := make(map[string]any, len(valid))
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	cases := map[string]string{
+		"expired":        signToken(t, signer, with("exp", time.Now().Add(-time.Hour).Unix())),
+		"no exp":         signToken(t, signer, with("exp", nil)),
+		"wrong issuer":   signToken(t, signer, with("iss", "https://evil.test")),
+		"wrong audience": signToken(t, signer, with("aud", "other")),
+		"foreign key":    signToken(t, otherSigner, valid),
+		"garbage":        "not.a.jwt",
+	}
+	for name, token := range cases {
+		if _, err := verifier.Verify(token); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("%s: expected ErrInvalidCredentials, got %v", name, err)
+		}
+	}
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	a, err := NewAuthenticator(config.AuthConfig{
+		APIKeys: []config.APIKeyConfig{{Name: "frontend", Hash: HashAPIKey("secret"), Scopes: []string{ScopeOrdersRead}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = a.Authenticate("", ""); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+	if _, err = a.Authenticate("Basic dXNlcjpwYXNz", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials for basic auth, got %v", err)
+	}
+	if _, err = a.Authenticate("Bearer token", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials without jwks, got %v", err)
+	}
+	if identity, err := a.Authenticate("", "secret"); err != nil || identity.Subject != "frontend" {
+		t.Fatalf("expected frontend identity, got %+v, %v", identity, err)
+	}
+}
+
+func newTestJWT(t *testing.T) (jose.Signer, *JWTVerifier) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &key.PublicKey, KeyID: "test", Algorithm: string(jose.ES256), Use: "sig"},
+	}}
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	verifier, err := NewJWTVerifier(path, "https://issuer.test", "web_demoservice")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.ES256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"),
+	)
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+
+	return signer, verifier
+}
+
+func signToken(t *testing.T, signer jose.Signer, claims map[string]any) string {
+	t.Helper()
+
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
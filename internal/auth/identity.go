@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+type Scope = string
+
+const (
+	ScopeOrdersRead    Scope = "orders:read"
+	ScopeOrdersReadPII Scope = "orders:read:pii"
+	// ScopeAdmin включает все остальные скоупы.
+	ScopeAdmin Scope = "admin"
+)
+
+type Method string
+
+const (
+	MethodAPIKey Method = "api_key"
+	MethodJWT    Method = "jwt"
+)
+
+// Identity — аутентифицированный клиент. Кладётся в контекст запроса для логов и аудита.
+type Identity struct {
+	Subject string
+	Method  Method
+	Scopes  []Scope
+}
+
+func (i *Identity) HasScope(scope Scope) bool {
+	if i == nil {
+		return false
+	}
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext возвращает клиента запроса; nil, если запрос не проходил аутентификацию.
+func FromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
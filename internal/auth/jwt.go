@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+const jwtLeeway = 30 * time.Second
+
+var jwtAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+type jwtClaims struct {
+	jwt.Claims
+	// Scope — скоупы через пробел (RFC 8693), Scp — массивом, как у части IdP.
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+}
+
+// JWTVerifier проверяет bearer-токены по ключам из локального JWKS файла.
+type JWTVerifier struct {
+	keys     jose.JSONWebKeySet
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+func NewJWTVerifier(jwksPath, issuer, audience string) (*JWTVerifier, error) {
+	data, err := os.ReadFile(jwksPath)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks %s: %w", jwksPath, err)
+	}
+
+	var keys jose.JSONWebKeySet
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parse jwks %s: %w", jwksPath, err)
+	}
+	if len(keys.Keys) == 0 {
+		return nil, fmt.Errorf("jwks %s has no keys", jwksPath)
+	}
+	for _, k := range keys.Keys {
+		if !k.IsPublic() {
+			return nil, fmt.Errorf("jwks %s: key %q is not a public key", jwksPath, k.KeyID)
+		}
+	}
+
+	return &JWTVerifier{keys: keys, issuer: issuer, audience: audience, now: time.Now}, nil
+}
+
+func (v *JWTVerifier) Verify(token string) (*Identity, error) {
+	parsed, err := jwt.ParseSigned(token, jwtAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	key, err := v.key(parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err = parsed.Claims(key.Key, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	expected := jwt.Expected{Issuer: v.issuer, Time: v.now()}
+	if v.audience != "" {
+		expected.AnyAudience = jwt.Audience{v.audience}
+	}
+	if err = claims.ValidateWithLeeway(expected, jwtLeeway); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("%w: token has no exp claim", ErrInvalidCredentials)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no sub claim", ErrInvalidCredentials)
+	}
+
+	scopes := append(strings.Fields(claims.Scope), claims.Scp...)
+	return &Identity{Subject: claims.Subject, Method: MethodJWT, Scopes: scopes}, nil
+}
+
+func (v *JWTVerifier) key(kid string) (*jose.JSONWebKey, error) {
+	if kid == "" {
+		if len(v.keys.Keys) == 1 {
+			return &v.keys.Keys[0], nil
+		}
+		return nil, fmt.Errorf("%w: token has no kid", ErrInvalidCredentials)
+	}
+
+	keys := v.keys.Key(kid)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: unknown kid %q", ErrInvalidCredentials, kid)
+	}
+	return &keys[0], nil
+}
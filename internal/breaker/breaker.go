@@ -0,0 +1,254 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/telemetry"
+)
+
+// ErrOpen возвращается без обращения к хранилищу, пока breaker открыт. Оборачивает
+// domain.ErrUnavailable: HTTP отвечает 503, консьюмер ставит чтение на паузу.
+var ErrOpen = fmt.Errorf("circuit breaker is open: %w", domain.ErrUnavailable)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// Settings: breaker открывается, если за Window было не меньше MinRequests вызовов и доля
+// отказов достигла FailureRate. Через OpenTimeout пропускается HalfOpenProbes пробных
+// вызовов; все успешны — breaker закрывается, любой отказ — снова открывается.
+// CallTimeout — дедлайн одного вызова (0 — без дедлайна).
+type Settings struct {
+	Window         time.Duration
+	MinRequests    int
+	FailureRate    float64
+	OpenTimeout    time.Duration
+	HalfOpenProbes int
+	CallTimeout    time.Duration
+}
+
+// windowBuckets — на сколько корзин делится окно: старые вызовы выпадают из окна по
+// корзине, а не все сразу.
+const windowBuckets = 10
+
+type bucket struct {
+	index    int64
+	total    int
+	failures int
+}
+
+type Breaker struct {
+	name     string
+	settings Settings
+	now      func() time.Time
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	openedAt   time.Time
+	buckets    [windowBuckets]bucket
+	probes     int
+	successes  int
+}
+
+func New(name string, settings Settings) *Breaker {
+	if settings.HalfOpenProbes < 1 {
+		settings.HalfOpenProbes = 1
+	}
+	if settings.Window <= 0 {
+		settings.Window = 30 * time.Second
+	}
+	b := &Breaker{name: name, settings: settings, now: time.Now}
+	telemetry.SetBreakerState(name, int(StateClosed))
+	return b
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(b.now())
+	return b.state
+}
+
+// Check — проверка готовности: открытый breaker означает, что хранилище недоступно.
+func (b *Breaker) Check(context.Context) error {
+	if state := b.State(); state == StateOpen {
+		return fmt.Errorf("circuit breaker %s is %s", b.name, state)
+	}
+	return nil
+}
+
+// Do выполняет fn с дедлайном CallTimeout, если breaker пропускает вызов.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return b.do(ctx, b.settings.CallTimeout, fn)
+}
+
+func (b *Breaker) do(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	generation, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	callCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err = fn(callCtx)
+	b.record(generation, outcome(ctx, err))
+	return err
+}
+
+type result int
+
+const (
+	resultSuccess result = iota
+	resultFailure
+	// resultIgnored: вызов отменил клиент — о здоровье хранилища это ничего не говорит.
+	resultIgnored
+)
+
+// outcome: отказом считаются только недоступность хранилища и истёкший дедлайн вызова;
+// not found, конфликты и ошибки валидации — нормальные ответы.
+func outcome(parent context.Context, err error) result {
+	switch {
+	case err == nil:
+		return resultSuccess
+	case parent.Err() != nil:
+		return resultIgnored
+	case errors.Is(err, domain.ErrUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return resultFailure
+	}
+	return resultSuccess
+}
+
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(b.now())
+
+	switch b.state {
+	case StateOpen:
+		telemetry.IncBreakerRejected(b.name)
+		return 0, ErrOpen
+	case StateHalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			telemetry.IncBreakerRejected(b.name)
+			return 0, ErrOpen
+		}
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+// advance переводит открытый breaker в half-open по истечении OpenTimeout.
+func (b *Breaker) advance(now time.Time) {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.settings.OpenTimeout {
+		b.setState(StateHalfOpen, now)
+	}
+}
+
+func (b *Breaker) record(generation uint64, res result) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// Вызов начат в предыдущем состоянии: его результат уже не важен.
+	if generation != b.generation {
+		return
+	}
+	now := b.now()
+
+	switch b.state {
+	case StateHalfOpen:
+		switch res {
+		case resultFailure:
+			b.setState(StateOpen, now)
+		case resultSuccess:
+			b.successes++
+			if b.successes >= b.settings.HalfOpenProbes {
+				b.setState(StateClosed, now)
+			}
+		case resultIgnored:
+			b.probes--
+		}
+	case StateClosed:
+		if res == resultIgnored {
+			return
+		}
+		cur := b.bucket(now)
+		cur.total++
+		if res == resultFailure {
+			cur.failures++
+			if b.tripped(now) {
+				b.setState(StateOpen, now)
+			}
+		}
+	}
+}
+
+func (b *Breaker) tripped(now time.Time) bool {
+	index := b.bucketIndex(now)
+	total, failures := 0, 0
+	for _, bk := range b.buckets {
+		if index-bk.index < windowBuckets {
+			total += bk.total
+			failures += bk.failures
+		}
+	}
+	return total >= b.settings.MinRequests && total > 0 &&
+		float64(failures)/float64(total) >= b.settings.FailureRate
+}
+
+func (b *Breaker) bucketIndex(now time.Time) int64 {
+	width := max(int64(b.settings.Window/windowBuckets), 1)
+	return now.UnixNano() / width
+}
+
+func (b *Breaker) bucket(now time.Time) *bucket {
+	index := b.bucketIndex(now)
+	bk := &b.buckets[index%windowBuckets]
+	if bk.index != index {
+		*bk = bucket{index: index}
+	}
+	return bk
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.probes, b.successes = 0, 0
+	switch state {
+	case StateOpen:
+		b.openedAt = now
+	case StateClosed:
+		b.buckets = [windowBuckets]bucket{}
+	}
+
+	telemetry.SetBreakerState(b.name, int(state))
+	slog.Warn("circuit breaker state changed",
+		slog.String("breaker", b.name),
+		slog.String("from", from.String()),
+		slog.String("to", state.String()))
+}
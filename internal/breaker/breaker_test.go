@@ -0,0 +1,130 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"web_demoservice/internal/domain"
+)
+
+var errDown = fmt.Errorf("%w: connection refused", domain.ErrUnavailable)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestBreaker(t *testing.T) (*Breaker, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	b := New(t.Name(), Settings{
+		Window:         10 * time.Second,
+		MinRequests:    4,
+		FailureRate:    0.5,
+		OpenTimeout:    5 * time.Second,
+		HalfOpenProbes: 2,
+	})
+	b.now = clock.now
+	return b, clock
+}
+
+func call(b *Breaker, err error) error {
+	return b.Do(context.Background(), func(context.Context) error { return err })
+}
+
+func TestBreaker_OpensOnFailureRate(t *testing.T) {
+	b, _ := newTestBreaker(t)
+
+	_ = call(b, nil)
+	_ = call(b, nil)
+	_ = call(b, errDown)
+	if b.State() != StateClosed {
+		t.Fatalf("breaker must stay closed below min requests")
+	}
+	_ = call(b, errDown)
+	if b.State() != StateOpen {
+		t.Fatalf("expected open at 50%% failures, got %s", b.State())
+	}
+
+	called := false
+	err := b.Do(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	})
+	if called || !errors.Is(err, ErrOpen) || !errors.Is(err, domain.ErrUnavailable) {
+		t.Fatalf("open breaker must reject without calling, got %v, called=%v", err, called)
+	}
+	if b.Check(context.Background()) == nil {
+		t.Fatalf("readiness must fail while open")
+	}
+}
+
+func TestBreaker_IgnoresNotFoundAndOldFailures(t *testing.T) {
+	b, clock := newTestBreaker(t)
+
+	_ = call(b, errDown)
+	_ = call(b, errDown)
+	clock.t = clock.t.Add(11 * time.Second)
+	_ = call(b, domain.ErrNotFound)
+	_ = call(b, nil)
+	_ = call(b, errDown)
+	if b.State() != StateClosed {
+		t.Fatalf("failures outside window and not found must not open breaker, got %s", b.State())
+	}
+}
+
+func TestBreaker_HalfOpenProbes(t *testing.T) {
+	b, clock := newTestBreaker(t)
+	for range 4 {
+		_ = call(b, errDown)
+	}
+	clock.t = clock.t.Add(5 * time.Second)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("expected half-open after open timeout, got %s", b.State())
+	}
+
+	// Пробный вызов провалился — снова открыт.
+	_ = call(b, errDown)
+	if b.State() != StateOpen {
+		t.Fatalf("failed probe must reopen, got %s", b.State())
+	}
+
+	clock.t = clock.t.Add(5 * time.Second)
+	_ = call(b, nil)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("one successful probe of two must keep half-open, got %s", b.State())
+	}
+	_ = call(b, nil)
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed after successful probes, got %s", b.State())
+	}
+}
+
+func TestBreaker_CallTimeout(t *testing.T) {
+	b := New(t.Name(), Settings{MinRequests: 1, FailureRate: 1, OpenTimeout: time.Minute, CallTimeout: 10 * time.Millisecond})
+
+	err := b.Do(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("timed out call must count as failure, got %s", b.State())
+	}
+}
+
+func TestBreaker_ClientCancelIsNotFailure(t *testing.T) {
+	b := New(t.Name(), Settings{MinRequests: 1, FailureRate: 1, OpenTimeout: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = b.Do(ctx, func(ctx context.Context) error { return ctx.Err() })
+	if b.State() != StateClosed {
+		t.Fatalf("client cancellation must not open breaker, got %s", b.State())
+	}
+}
@@ -0,0 +1,125 @@
+package breaker
+
+import (
+	"context"
+	"web_demoservice/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type OrderRepository interface {
+	Create(ctx context.Context, order domain.OrderWithInformation) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	GetPartsByID(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error)
+	GetAllLast24Hours(ctx context.Context) ([]domain.OrderWithInformation, error)
+	List(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error)
+}
+
+// CustomerRepository — выборки и анонимизация для запросов клиентов (GDPR).
+type CustomerRepository interface {
+	FindOrderIDsByCustomer(ctx context.Context, customerID string) ([]uuid.UUID, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	AnonymizeDeliveries(ctx context.Context, orderIDs []uuid.UUID) (int, error)
+}
+
+// WrapOrderRepository пропускает вызовы репозитория через breaker. Прогрев кэша
+// (GetAllLast24Hours) читает много строк и идёт без дедлайна вызова.
+func WrapOrderRepository(next OrderRepository, b *Breaker) OrderRepository {
+	return &orderRepositoryBreaker{next: next, breaker: b}
+}
+
+type orderRepositoryBreaker struct {
+	next    OrderRepository
+	breaker *Breaker
+}
+
+func (r *orderRepositoryBreaker) Create(ctx context.Context, order domain.OrderWithInformation) (bool, error) {
+	var created bool
+	err := r.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = r.next.Create(ctx, order)
+		return err
+	})
+	return created, err
+}
+
+func (r *orderRepositoryBreaker) GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+	var order *domain.OrderWithInformation
+	err := r.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = r.next.GetByID(ctx, id)
+		return err
+	})
+	return order, err
+}
+
+func (r *orderRepositoryBreaker) GetPartsByID(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
+	var order *domain.OrderWithInformation
+	err := r.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = r.next.GetPartsByID(ctx, id, include)
+		return err
+	})
+	return order, err
+}
+
+func (r *orderRepositoryBreaker) GetAllLast24Hours(ctx context.Context) ([]domain.OrderWithInformation, error) {
+	var orders []domain.OrderWithInformation
+	err := r.breaker.do(ctx, 0, func(ctx context.Context) error {
+		var err error
+		orders, err = r.next.GetAllLast24Hours(ctx)
+		return err
+	})
+	return orders, err
+}
+
+func (r *orderRepositoryBreaker) List(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+	var orders []domain.OrderWithInformation
+	err := r.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		orders, err = r.next.List(ctx, after, limit)
+		return err
+	})
+	return orders, err
+}
+
+// WrapCustomerRepository пропускает запросы клиентов через тот же breaker, что и заказы:
+// они читают те же таблицы.
+func WrapCustomerRepository(next CustomerRepository, b *Breaker) CustomerRepository {
+	return &customerRepositoryBreaker{next: next, breaker: b}
+}
+
+type customerRepositoryBreaker struct {
+	next    CustomerRepository
+	breaker *Breaker
+}
+
+func (r *customerRepositoryBreaker) FindOrderIDsByCustomer(ctx context.Context, customerID string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		ids, err = r.next.FindOrderIDsByCustomer(ctx, customerID)
+		return err
+	})
+	return ids, err
+}
+
+func (r *customerRepositoryBreaker) GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+	var order *domain.OrderWithInformation
+	err := r.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = r.next.GetByID(ctx, id)
+		return err
+	})
+	return order, err
+}
+
+func (r *customerRepositoryBreaker) AnonymizeDeliveries(ctx context.Context, orderIDs []uuid.UUID) (int, error) {
+	var anonymized int
+	err := r.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		anonymized, err = r.next.AnonymizeDeliveries(ctx, orderIDs)
+		return err
+	})
+	return anonymized, err
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 	"web_demoservice/internal/domain"
@@ -10,34 +11,67 @@ import (
 )
 
 type cacheEntity struct {
-	order domain.OrderWithInformation
-	time  time.Time
+	order   domain.OrderWithInformation
+	version domain.OrderVersion
+	// time — последнее обращение (от него считается TTL), created — когда заказ положен в кэш.
+	time    time.Time
+	created time.Time
+	size    int
+}
+
+// Stats — состояние кэша для метрик; Expired и Evicted накапливаются с момента старта.
+type Stats struct {
+	Entries int
+	Bytes   int64
+	Expired uint64
+	Evicted uint64
+	TTL     time.Duration
 }
 
 type Cache struct {
-	mu    sync.RWMutex
-	cache map[uuid.UUID]cacheEntity
-	ttl   time.Duration
-	timer *time.Ticker
-	done  chan struct{}
+	mu      sync.RWMutex
+	cache   map[uuid.UUID]cacheEntity
+	ttl     time.Duration
+	timer   *time.Ticker
+	done    chan struct{}
+	bytes   int64
+	expired uint64
+	evicted uint64
+	// held — заказы, которые сейчас анонимизируются: Set для них ничего не делает.
+	held map[uuid.UUID]int
 }
 
 func NewCache(ttl time.Duration) *Cache {
 	return &Cache{
 		cache: make(map[uuid.UUID]cacheEntity),
+		held:  make(map[uuid.UUID]int),
 		ttl:   ttl,
 		done:  make(chan struct{}),
 	}
 }
 
 func (c *Cache) Set(ctx context.Context, id uuid.UUID, order domain.OrderWithInformation) {
+	// Размер — длина того же JSON, из которого считается ETag.
+	version, size := domain.NewOrderVersionSize(order)
+	now := time.Now()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.held[id] > 0 {
+		return
+	}
+	if old, ok := c.cache[id]; ok {
+		c.bytes -= int64(old.size)
+	}
 	entity := cacheEntity{
-		order: order,
-		time:  time.Now(),
+		order:   order,
+		version: version,
+		time:    now,
+		created: now,
+		size:    size,
 	}
 	c.cache[id] = entity
+	c.bytes += int64(size)
 }
 
 func (c *Cache) Get(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, bool) {
@@ -54,8 +88,145 @@ func (c *Cache) Get(ctx context.Context, id uuid.UUID) (*domain.OrderWithInforma
 	return &entity.order, true
 }
 
+// Version отдаёт ETag/Last-Modified закэшированного заказа, не копируя сам заказ.
+func (c *Cache) Version(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entity, ok := c.cache[id]
+	if !ok {
+		return nil, false
+	}
+
+	entity.time = time.Now()
+	c.cache[id] = entity
+
+	return &entity.version, true
+}
+
+// Delete убирает заказ из кэша, например после анонимизации или через админский API.
+func (c *Cache) Delete(ctx context.Context, id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(id)
+}
+
+// Hold убирает заказы из кэша и не даёт положить их обратно, пока не вызван release:
+// чтение, начатое до коммита анонимизации, не вернёт в кэш старые данные.
+func (c *Cache) Hold(ctx context.Context, ids []uuid.UUID) (release func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		c.held[id]++
+		c.evict(id)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			for _, id := range ids {
+				if c.held[id]--; c.held[id] <= 0 {
+					delete(c.held, id)
+				}
+			}
+		})
+	}
+}
+
+// Flush очищает кэш целиком и возвращает число удалённых записей.
+func (c *Cache) Flush(ctx context.Context) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.cache)
+	c.cache = make(map[uuid.UUID]cacheEntity)
+	c.bytes = 0
+	c.evicted += uint64(n)
+	return n
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Stats{
+		Entries: len(c.cache),
+		Bytes:   c.bytes,
+		Expired: c.expired,
+		Evicted: c.evicted,
+		TTL:     c.ttl,
+	}
+}
+
+// Entries возвращает метаданные всех записей, отсортированные по ID. Время доступа не меняется.
+func (c *Cache) Entries() []domain.CacheEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]domain.CacheEntry, 0, len(c.cache))
+	for id, entity := range c.cache {
+		out = append(out, c.info(id, entity))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].OrderID.String() < out[j].OrderID.String() })
+	return out
+}
+
+// RangeCreated обходит время создания записей под read-локом без копирования и сортировки
+// (для метрик на каждом скрейпе); fn не должен обращаться к кэшу.
+func (c *Cache) RangeCreated(fn func(created time.Time)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, entity := range c.cache {
+		fn(entity.created)
+	}
+}
+
+// Entry возвращает метаданные записи; время доступа не меняется.
+func (c *Cache) Entry(id uuid.UUID) (domain.CacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entity, ok := c.cache[id]
+	if !ok {
+		return domain.CacheEntry{}, false
+	}
+	return c.info(id, entity), true
+}
+
+func (c *Cache) info(id uuid.UUID, entity cacheEntity) domain.CacheEntry {
+	return domain.CacheEntry{
+		OrderID:    id,
+		CreatedAt:  entity.created,
+		LastAccess: entity.time,
+		ExpiresAt:  entity.time.Add(c.ttl),
+		Size:       entity.size,
+		ETag:       entity.version.ETag,
+	}
+}
+
+func (c *Cache) evict(id uuid.UUID) {
+	if entity, ok := c.cache[id]; ok {
+		c.remove(id, entity)
+		c.evicted++
+	}
+}
+
+func (c *Cache) remove(id uuid.UUID, entity cacheEntity) {
+	delete(c.cache, id)
+	c.bytes -= int64(entity.size)
+}
+
+// SetTTL меняет TTL на лету (hot reload конфига); период очистки пересчитывается.
+func (c *Cache) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	if c.timer != nil {
+		c.timer.Reset(cleanupPeriod(ttl))
+	}
+}
+
 func (c *Cache) StartDeleting(ctx context.Context) {
-	c.timer = time.NewTicker(c.ttl / 2)
+	c.mu.Lock()
+	c.timer = time.NewTicker(cleanupPeriod(c.ttl))
+	c.mu.Unlock()
 
 	go func() {
 		for {
@@ -70,7 +241,8 @@ func (c *Cache) StartDeleting(ctx context.Context) {
 				c.mu.Lock()
 				for id, entity := range c.cache {
 					if time.Since(entity.time) > c.ttl {
-						delete(c.cache, id)
+						c.remove(id, entity)
+						c.expired++
 					}
 				}
 				c.mu.Unlock()
@@ -82,3 +254,8 @@ func (c *Cache) StartDeleting(ctx context.Context) {
 func (c *Cache) StopDeleting() {
 	close(c.done)
 }
+
+// cleanupPeriod — половина TTL, но не меньше миллисекунды: тикер с нулевым периодом паникует.
+func cleanupPeriod(ttl time.Duration) time.Duration {
+	return max(ttl/2, time.Millisecond)
+}
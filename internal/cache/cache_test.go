@@ -31,6 +31,45 @@ func TestCache_SetGet_Hit(t *testing.T) {
 	}
 }
 
+func TestCache_Delete(t *testing.T) {
+	c := NewCache(time.Minute)
+	id := uuid.New()
+
+	c.Set(context.Background(), id, sampleOrder(id))
+	c.Delete(context.Background(), id)
+
+	if _, ok := c.Get(context.Background(), id); ok {
+		t.Fatalf("expected deleted order to be a cache miss")
+	}
+	if _, ok := c.Version(context.Background(), id); ok {
+		t.Fatalf("expected deleted order to have no version")
+	}
+}
+
+func TestCache_HoldBlocksSet(t *testing.T) {
+	c := NewCache(time.Minute)
+	id := uuid.New()
+	c.Set(context.Background(), id, sampleOrder(id))
+
+	release := c.Hold(context.Background(), []uuid.UUID{id})
+	if _, ok := c.Get(context.Background(), id); ok {
+		t.Fatalf("expected held order to be evicted")
+	}
+
+	// Чтение, начатое до анонимизации, пытается вернуть старую версию в кэш.
+	c.Set(context.Background(), id, sampleOrder(id))
+	if _, ok := c.Get(context.Background(), id); ok {
+		t.Fatalf("expected set of held order to be ignored")
+	}
+
+	release()
+	release()
+	c.Set(context.Background(), id, sampleOrder(id))
+	if _, ok := c.Get(context.Background(), id); !ok {
+		t.Fatalf("expected order to be cached after release")
+	}
+}
+
 func TestCache_Expiration(t *testing.T) {
 	ttl := 60 * time.Millisecond
 	c := NewCache(ttl)
@@ -52,6 +91,99 @@ func TestCache_Expiration(t *testing.T) {
 	if _, ok := c.Get(context.Background(), id); ok {
 		t.Fatalf("expected cache entry to expire")
 	}
+	if stats := c.Stats(); stats.Expired != 1 || stats.Bytes != 0 {
+		t.Fatalf("expected one expiration, got %+v", stats)
+	}
+}
+
+func TestCache_SetTTL(t *testing.T) {
+	c := NewCache(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	c.StartDeleting(ctx)
+
+	ttl := 60 * time.Millisecond
+	c.SetTTL(ttl)
+
+	id := uuid.New()
+	c.Set(context.Background(), id, sampleOrder(id))
+
+	time.Sleep(2*ttl + ttl/2)
+	if _, ok := c.Get(context.Background(), id); ok {
+		t.Fatalf("expected entry to expire with the new ttl")
+	}
+}
+
+func TestCache_Version(t *testing.T) {
+	c := NewCache(time.Minute)
+	id := uuid.New()
+	order := sampleOrder(id)
+
+	if _, ok := c.Version(context.Background(), id); ok {
+		t.Fatalf("expected version miss before Set")
+	}
+
+	c.Set(context.Background(), id, order)
+	got, ok := c.Version(context.Background(), id)
+	if !ok {
+		t.Fatalf("expected version hit")
+	}
+	if want := domain.NewOrderVersion(order); *got != want {
+		t.Fatalf("expected version %+v, got %+v", want, *got)
+	}
+
+	order.Items[0].Status = 2
+	c.Set(context.Background(), id, order)
+	changed, _ := c.Version(context.Background(), id)
+	if changed.ETag == got.ETag {
+		t.Fatalf("expected ETag to change with content")
+	}
+}
+
+func TestCache_StatsAndEntries(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache(time.Minute)
+	first, second := uuid.New(), uuid.New()
+
+	c.Set(ctx, first, sampleOrder(first))
+	c.Set(ctx, second, sampleOrder(second))
+	c.Set(ctx, second, sampleOrder(second))
+
+	stats := c.Stats()
+	if stats.Entries != 2 || stats.Bytes <= 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	entry, ok := c.Entry(first)
+	if !ok || entry.Size <= 0 || entry.ETag == "" || !entry.ExpiresAt.Equal(entry.LastAccess.Add(time.Minute)) {
+		t.Fatalf("unexpected entry: %+v, %v", entry, ok)
+	}
+	if stats.Bytes != int64(2*entry.Size) {
+		t.Fatalf("expected bytes of two entries, got %d (entry %d)", stats.Bytes, entry.Size)
+	}
+	entries := c.Entries()
+	if len(entries) != 2 || entries[0].OrderID.String() > entries[1].OrderID.String() {
+		t.Fatalf("expected entries sorted by id, got %+v", entries)
+	}
+	var created int
+	c.RangeCreated(func(at time.Time) {
+		if !at.IsZero() {
+			created++
+		}
+	})
+	if created != 2 {
+		t.Fatalf("expected creation time of 2 entries, got %d", created)
+	}
+
+	c.Delete(ctx, first)
+	c.Delete(ctx, first)
+	if n := c.Flush(ctx); n != 1 {
+		t.Fatalf("expected 1 flushed entry, got %d", n)
+	}
+	stats = c.Stats()
+	if stats.Entries != 0 || stats.Bytes != 0 || stats.Evicted != 2 || stats.Expired != 0 {
+		t.Fatalf("unexpected stats after eviction: %+v", stats)
+	}
 }
 
 func sampleOrder(id uuid.UUID) domain.OrderWithInformation {
@@ -120,3 +252,12 @@ func sampleOrder(id uuid.UUID) domain.OrderWithInformation {
 		},
 	}
 }
+
+func TestCache_TinyTTLDoesNotPanic(t *testing.T) {
+	c := NewCache(time.Nanosecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	c.StartDeleting(ctx)
+	c.SetTTL(time.Nanosecond)
+}
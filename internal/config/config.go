@@ -2,45 +2,95 @@ package config
 
 import (
 	"fmt"
-	"os"
 	"time"
-
-	"github.com/BurntSushi/toml"
 )
 
-func NewConfig(path string) (*Config, error) {
-	_, err := os.Stat(path)
-	if err != nil {
-		return nil, fmt.Errorf("config file %s does not exist; err: %w", path, err)
-	}
-
-	var cfg Config
-	if _, err = toml.DecodeFile(path, &cfg); err != nil {
-		return nil, fmt.Errorf("error parsing config file %s; err: %w", path, err)
-	}
-
-	return &cfg, nil
-}
-
 type Config struct {
-	HTTP      HTTPConfig      `toml:"http"`
-	DB        PostgresConfig  `toml:"db"`
-	Kafka     KafkaConfig     `toml:"kafka"`
-	Telemetry TelemetryConfig `toml:"telemetry"`
-	Metrics   MetricsConfig   `toml:"metrics"`
+	HTTP       HTTPConfig       `toml:"http"`
+	GRPC       GRPCConfig       `toml:"grpc"`
+	DB         PostgresConfig   `toml:"db"`
+	Kafka      KafkaConfig      `toml:"kafka"`
+	Telemetry  TelemetryConfig  `toml:"telemetry"`
+	Metrics    MetricsConfig    `toml:"metrics"`
+	Auth       AuthConfig       `toml:"auth"`
+	PII        PIIConfig        `toml:"pii"`
+	Encryption EncryptionConfig `toml:"encryption"`
+	RateLimit  RateLimitConfig  `toml:"rate_limit"`
+	Audit      AuditConfig      `toml:"audit"`
+	Log        LogConfig        `toml:"log"`
 }
 
+// HTTPConfig: таймауты и лимиты — как у http.Server; max_body_bytes ограничивает тело
+// запросов /api/v1. shutdown_timeout — сколько ждать активные запросы при остановке.
 type HTTPConfig struct {
 	Host     string        `toml:"host"`
 	Port     int           `toml:"port"`
 	CacheTTL time.Duration `toml:"cache_ttl"`
+	DevMode  bool          `toml:"dev_mode"`
+
+	ReadHeaderTimeout time.Duration `toml:"read_header_timeout"`
+	ReadTimeout       time.Duration `toml:"read_timeout"`
+	WriteTimeout      time.Duration `toml:"write_timeout"`
+	IdleTimeout       time.Duration `toml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `toml:"shutdown_timeout"`
+	MaxHeaderBytes    int           `toml:"max_header_bytes"`
+	MaxBodyBytes      int64         `toml:"max_body_bytes"`
+
+	TLS   TLSConfig           `toml:"tls"`
+	CORS  CORSConfig          `toml:"cors"`
+	Admin AdminListenerConfig `toml:"admin"`
 }
 
+// TLSConfig: client_ca_file включает mTLS — без сертификата, подписанного этим CA,
+// соединение не устанавливается. min_version — "1.2" или "1.3".
+type TLSConfig struct {
+	Enabled      bool   `toml:"enabled"`
+	CertFile     string `toml:"cert_file"`
+	KeyFile      string `toml:"key_file"`
+	ClientCAFile string `toml:"client_ca_file"`
+	MinVersion   string `toml:"min_version"`
+}
+
+// CORSConfig: пустой allowed_origins — кросс-доменные запросы из браузера запрещены
+// (веб-интерфейс отдаётся с того же origin и CORS не требует).
+type CORSConfig struct {
+	AllowedOrigins   []string      `toml:"allowed_origins"`
+	AllowedMethods   []string      `toml:"allowed_methods"`
+	AllowedHeaders   []string      `toml:"allowed_headers"`
+	AllowCredentials bool          `toml:"allow_credentials"`
+	MaxAge           time.Duration `toml:"max_age"`
+}
+
+// AdminListenerConfig — отдельный listener для /metrics, /api/v1/admin и /debug: при enabled
+// эти маршруты снимаются с основного порта. Таймауты и TLS свои: write_timeout должен
+// вмещать pprof-профиль, а mTLS публичного API не должен ломать скрейп метрик.
+type AdminListenerConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Host    string `toml:"host"`
+	Port    int    `toml:"port"`
+
+	ReadHeaderTimeout time.Duration `toml:"read_header_timeout"`
+	ReadTimeout       time.Duration `toml:"read_timeout"`
+	WriteTimeout      time.Duration `toml:"write_timeout"`
+	IdleTimeout       time.Duration `toml:"idle_timeout"`
+
+	TLS TLSConfig `toml:"tls"`
+}
+
+type GRPCConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Host    string `toml:"host"`
+	Port    int    `toml:"port"`
+}
+
+// PostgresConfig: url — полный DSN; если задан, host/port/user/password/database/sslmode
+// не используются.
 type PostgresConfig struct {
+	URL      string `toml:"url" secret:"true"`
 	Host     string `toml:"host"`
 	Port     int    `toml:"port"`
 	User     string `toml:"user"`
-	Password string `toml:"password"`
+	Password string `toml:"password" secret:"true"`
 	Database string `toml:"database"`
 	SSLMode  string `toml:"sslmode"`
 
@@ -48,20 +98,54 @@ type PostgresConfig struct {
 	MinConns          int32         `toml:"min_conns"`
 	MaxConnLifetime   time.Duration `toml:"max_conn_lifetime"`
 	HealthCheckPeriod time.Duration `toml:"health_check_period"`
+
+	// DegradedStart: если БД не ответила за retry.attempts попыток, сервис всё равно
+	// стартует — API отдаёт заказы из кэша, /readyz не проходит до восстановления БД.
+	DegradedStart bool          `toml:"degraded_start"`
+	Retry         RetryConfig   `toml:"retry"`
+	Breaker       BreakerConfig `toml:"breaker"`
+}
+
+// BreakerConfig — circuit breaker вокруг репозитория заказов: открывается, если за window
+// было не меньше min_requests вызовов и доля отказов достигла failure_rate. Через
+// open_timeout пропускается half_open_probes пробных вызовов. call_timeout — дедлайн
+// одного запроса к БД.
+type BreakerConfig struct {
+	Enabled        bool          `toml:"enabled"`
+	Window         time.Duration `toml:"window"`
+	MinRequests    int           `toml:"min_requests"`
+	FailureRate    float64       `toml:"failure_rate"`
+	OpenTimeout    time.Duration `toml:"open_timeout"`
+	HalfOpenProbes int           `toml:"half_open_probes"`
+	CallTimeout    time.Duration `toml:"call_timeout"`
+}
+
+// RetryConfig — повтор подключения к зависимости при старте: до attempts попыток с паузой
+// от initial_backoff, растущей вдвое до max_backoff. attempts = 0 — без ограничения.
+type RetryConfig struct {
+	Attempts       int           `toml:"attempts"`
+	InitialBackoff time.Duration `toml:"initial_backoff"`
+	MaxBackoff     time.Duration `toml:"max_backoff"`
 }
 
 func (p *PostgresConfig) DSN() string {
+	if p.URL != "" {
+		return p.URL
+	}
 	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		p.User, p.Password, p.Host, p.Port, p.Database, p.SSLMode,
 	)
 }
 
+// KafkaConfig: lag_interval — как часто запрашивать у брокера лаг группы для метрик.
 type KafkaConfig struct {
-	Brokers  []string `toml:"brokers"`
-	Topic    string   `toml:"topic"`
-	GroupID  string   `toml:"group_id"`
-	DLQTopic string   `toml:"dlq_topic"`
+	Brokers     []string      `toml:"brokers"`
+	Topic       string        `toml:"topic"`
+	GroupID     string        `toml:"group_id"`
+	DLQTopic    string        `toml:"dlq_topic"`
+	LagInterval time.Duration `toml:"lag_interval"`
+	Retry       RetryConfig   `toml:"retry"`
 }
 
 type TelemetryConfig struct {
@@ -76,3 +160,67 @@ type MetricsConfig struct {
 	Enabled bool   `toml:"enabled"`
 	Path    string `toml:"path"`
 }
+
+type AuthConfig struct {
+	Enabled  bool           `toml:"enabled"`
+	JWKSFile string         `toml:"jwks_file"`
+	Issuer   string         `toml:"issuer"`
+	Audience string         `toml:"audience"`
+	APIKeys  []APIKeyConfig `toml:"api_keys"`
+}
+
+// APIKeyConfig хранит sha256-хэш ключа ("sha256:<hex>"), сам ключ в конфиг не попадает.
+type APIKeyConfig struct {
+	Name   string   `toml:"name"`
+	Hash   string   `toml:"hash" secret:"true"`
+	Scopes []string `toml:"scopes"`
+}
+
+// PIIConfig: rules — правило маскирования для поля delivery, поверх pii.DefaultRules.
+type PIIConfig struct {
+	Rules map[string]string `toml:"rules"`
+}
+
+// EncryptionConfig — шифрование PII доставки в БД. Keyfile создаётся командой cmd/keyring.
+type EncryptionConfig struct {
+	Enabled           bool   `toml:"enabled"`
+	Keyfile           string `toml:"keyfile"`
+	RotationBatchSize int    `toml:"rotation_batch_size"`
+}
+
+// RateLimitConfig: groups — лимиты групп маршрутов ("orders", "public"); группа без
+// лимита не ограничивается.
+type RateLimitConfig struct {
+	Enabled           bool                            `toml:"enabled"`
+	TrustForwardedFor bool                            `toml:"trust_forwarded_for"`
+	Groups            map[string]RateLimitGroupConfig `toml:"groups"`
+}
+
+type RateLimitGroupConfig struct {
+	Rate  float64 `toml:"rate"`
+	Burst int     `toml:"burst"`
+}
+
+// AuditConfig — журнал аудита audit.events. События копятся в буфере buffer_size и пишутся
+// пачками по batch_size не реже flush_interval.
+type AuditConfig struct {
+	Enabled       bool          `toml:"enabled"`
+	BufferSize    int           `toml:"buffer_size"`
+	BatchSize     int           `toml:"batch_size"`
+	FlushInterval time.Duration `toml:"flush_interval"`
+}
+
+// LogConfig: level — debug, info, warn или error (как у slog.Level), format — text или json.
+type LogConfig struct {
+	Level    string            `toml:"level"`
+	Format   string            `toml:"format"`
+	Sampling LogSamplingConfig `toml:"sampling"`
+}
+
+// LogSamplingConfig: за каждый tick одинаковых записей (уровень и сообщение) ниже error
+// пишутся первые initial, затем каждая thereafter-я. initial = 0 выключает семплирование.
+type LogSamplingConfig struct {
+	Initial    int           `toml:"initial"`
+	Thereafter int           `toml:"thereafter"`
+	Tick       time.Duration `toml:"tick"`
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Diff возвращает пути изменённых полей ("http.cache_ttl", "rate_limit.groups"). Таблицы и
+// списки сравниваются целиком.
+func Diff(prev, next *Config) []string {
+	return diffValues(reflect.ValueOf(*prev), reflect.ValueOf(*next), "")
+}
+
+func diffValues(prev, next reflect.Value, prefix string) []string {
+	var changed []string
+	for i := 0; i < prev.NumField(); i++ {
+		name, _, _ := strings.Cut(prev.Type().Field(i).Tag.Get("toml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+
+		pf, nf := prev.Field(i), next.Field(i)
+		if pf.Kind() == reflect.Struct {
+			changed = append(changed, diffValues(pf, nf, path+".")...)
+			continue
+		}
+		if !reflect.DeepEqual(pf.Interface(), nf.Interface()) {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
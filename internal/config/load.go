@@ -0,0 +1,263 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	// EnvPrefix — префикс переменных окружения: WEB_DEMOSERVICE_DB_PASSWORD для db.password.
+	EnvPrefix = "WEB_DEMOSERVICE_"
+	// fileSuffix: WEB_DEMOSERVICE_DB_PASSWORD_FILE=/run/secrets/db_password читает значение из файла.
+	fileSuffix = "_FILE"
+
+	defaultConfigPath = "./config.toml"
+)
+
+// Options — параметры запуска, которые не входят в Config.
+type Options struct {
+	Path        string
+	PrintConfig bool
+}
+
+// Load собирает конфиг слоями: Defaults, TOML-файл из --config (или WEB_DEMOSERVICE_CONFIG),
+// переменные WEB_DEMOSERVICE_*, флаги вида --db.password. Каждый следующий слой
+// перекрывает предыдущий. Неизвестные ключи файла, ошибки env/флагов и Validate
+// возвращаются вместе. Через env и флаги задаются только скалярные поля и списки строк;
+// таблицы (auth.api_keys, pii.rules, rate_limit.groups) — только в файле.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, Options, error) {
+	cfg := Defaults()
+	leaves := collectLeaves(reflect.ValueOf(&cfg).Elem(), "")
+
+	opts := Options{Path: defaultConfigPath}
+	explicitPath := false
+	if path, ok := lookupEnv(EnvPrefix + "CONFIG"); ok && path != "" {
+		opts.Path, explicitPath = path, true
+	}
+
+	fs := flag.NewFlagSet("web_demoservice", flag.ContinueOnError)
+	fs.Func("config", "path to TOML config (env "+EnvPrefix+"CONFIG, default "+defaultConfigPath+")", func(s string) error {
+		opts.Path, explicitPath = s, true
+		return nil
+	})
+	fs.BoolVar(&opts.PrintConfig, "print-config", false, "print effective config with secrets redacted and exit")
+	flagValues := make([]*flagValue, 0, len(leaves))
+	for _, l := range leaves {
+		fv := &flagValue{leaf: l}
+		flagValues = append(flagValues, fv)
+		fs.Var(fv, l.path, "env "+l.envName())
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, opts, err
+	}
+	if fs.NArg() > 0 {
+		return nil, opts, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	var errs []error
+	// Файл по умолчанию необязателен: сервис может настраиваться только через env.
+	if _, err := os.Stat(opts.Path); err == nil || explicitPath {
+		md, err := toml.DecodeFile(opts.Path, &cfg)
+		if err != nil {
+			return nil, opts, fmt.Errorf("error parsing config file %s; err: %w", opts.Path, err)
+		}
+		for _, key := range md.Undecoded() {
+			errs = append(errs, fmt.Errorf("%s: unknown key in %s", key, opts.Path))
+		}
+	}
+
+	for _, l := range leaves {
+		if err := l.applyEnv(lookupEnv); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, fv := range flagValues {
+		if fv.set {
+			if err := fv.leaf.set(fv.raw); err != nil {
+				errs = append(errs, fmt.Errorf("flag --%s: %w", fv.leaf.path, err))
+			}
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, opts, fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+	}
+
+	return &cfg, opts, nil
+}
+
+// Defaults — значения, которые действуют, если их не задал ни один слой.
+func Defaults() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Host: "0.0.0.0", Port: 8080, CacheTTL: 10 * time.Minute,
+			ReadHeaderTimeout: 5 * time.Second, ReadTimeout: 15 * time.Second, WriteTimeout: 30 * time.Second,
+			IdleTimeout: 2 * time.Minute, ShutdownTimeout: 10 * time.Second,
+			MaxHeaderBytes: 64 << 10, MaxBodyBytes: 1 << 20,
+			TLS: TLSConfig{MinVersion: "1.2"},
+			CORS: CORSConfig{
+				AllowedMethods: []string{"GET", "HEAD"},
+				AllowedHeaders: []string{"Content-Type", "Accept", "If-None-Match", "If-Modified-Since", "Authorization", "X-API-Key", "X-Request-ID"},
+				MaxAge:         10 * time.Minute,
+			},
+			Admin: AdminListenerConfig{
+				Host: "127.0.0.1", Port: 9091,
+				ReadHeaderTimeout: 5 * time.Second, ReadTimeout: 15 * time.Second, WriteTimeout: 2 * time.Minute,
+				IdleTimeout: 2 * time.Minute,
+				TLS:         TLSConfig{MinVersion: "1.2"},
+			},
+		},
+		GRPC: GRPCConfig{Host: "0.0.0.0", Port: 50051},
+		DB: PostgresConfig{
+			Host: "localhost", Port: 5432, SSLMode: "disable",
+			MaxConns: 10, MinConns: 2, HealthCheckPeriod: time.Minute,
+			Retry: RetryConfig{Attempts: 10, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second},
+			Breaker: BreakerConfig{
+				Enabled: true, Window: 30 * time.Second, MinRequests: 10, FailureRate: 0.5,
+				OpenTimeout: 10 * time.Second, HalfOpenProbes: 3, CallTimeout: 2 * time.Second,
+			},
+		},
+		Kafka: KafkaConfig{
+			Brokers: []string{"localhost:19092"}, Topic: "orders",
+			GroupID: "order-processor", DLQTopic: "orders_dlq", LagInterval: 15 * time.Second,
+			Retry: RetryConfig{Attempts: 10, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second},
+		},
+		Telemetry:  TelemetryConfig{ServiceName: "web_demoservice", OTLPEndpoint: "localhost:4317", SampleRatio: 1.0},
+		Metrics:    MetricsConfig{Enabled: true, Path: "/metrics"},
+		Encryption: EncryptionConfig{Keyfile: "keys/keyring.json", RotationBatchSize: 500},
+		Audit:      AuditConfig{BufferSize: 1024, BatchSize: 100, FlushInterval: time.Second},
+		Log:        LogConfig{Level: "info", Format: "text", Sampling: LogSamplingConfig{Thereafter: 100, Tick: time.Second}},
+	}
+}
+
+// leaf — скалярное поле конфига, которое можно задать через env и флаг.
+type leaf struct {
+	path   string
+	value  reflect.Value
+	secret bool
+}
+
+func (l leaf) envName() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(l.path, ".", "_"))
+}
+
+func (l leaf) applyEnv(lookupEnv func(string) (string, bool)) error {
+	name := l.envName()
+	raw, ok := lookupEnv(name)
+	file, fromFile := lookupEnv(name + fileSuffix)
+	switch {
+	case ok && fromFile:
+		return fmt.Errorf("env %s and %s%s are both set", name, name, fileSuffix)
+	case fromFile:
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("env %s%s: %w", name, fileSuffix, err)
+		}
+		raw = strings.TrimRight(string(content), "\r\n")
+	case !ok:
+		return nil
+	}
+
+	if err := l.set(raw); err != nil {
+		return fmt.Errorf("env %s: %w", name, err)
+	}
+	return nil
+}
+
+func (l leaf) set(raw string) error {
+	v := l.value
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
+
+// collectLeaves обходит структуру по toml-тегам; таблицы и списки таблиц пропускаются.
+func collectLeaves(v reflect.Value, prefix string) []leaf {
+	var leaves []leaf
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+
+		fv := v.Field(i)
+		switch {
+		case fv.Kind() == reflect.Struct:
+			leaves = append(leaves, collectLeaves(fv, path+".")...)
+		case fv.Kind() == reflect.Map:
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.String:
+		default:
+			leaves = append(leaves, leaf{path: path, value: fv, secret: field.Tag.Get("secret") == "true"})
+		}
+	}
+	return leaves
+}
+
+// flagValue откладывает применение флага до того, как прочитаны файл и env.
+type flagValue struct {
+	leaf leaf
+	raw  string
+	set  bool
+}
+
+func (f *flagValue) String() string {
+	return ""
+}
+
+func (f *flagValue) Set(s string) error {
+	f.raw, f.set = s, true
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.leaf.value.IsValid() && f.leaf.value.Kind() == reflect.Bool
+}
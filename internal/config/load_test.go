@@ -0,0 +1,147 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func envMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestLoad_Layers(t *testing.T) {
+	path := writeConfig(t, `
+[http]
+port = 9000
+cache_ttl = "5m"
+
+[db]
+host = "file-host"
+user = "file-user"
+database = "file-db"
+`)
+	env := envMap(map[string]string{
+		"WEB_DEMOSERVICE_DB_HOST":       "env-host",
+		"WEB_DEMOSERVICE_HTTP_PORT":     "9100",
+		"WEB_DEMOSERVICE_KAFKA_BROKERS": "k1:9092, k2:9092",
+	})
+
+	cfg, opts, err := Load([]string{"--config", path, "--http.port=9200", "--telemetry.enabled"}, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if opts.Path != path || opts.PrintConfig {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	if cfg.HTTP.Port != 9200 {
+		t.Fatalf("expected flag to override env and file, got port %d", cfg.HTTP.Port)
+	}
+	if cfg.DB.Host != "env-host" || cfg.DB.User != "file-user" {
+		t.Fatalf("expected env to override file, got host %q user %q", cfg.DB.Host, cfg.DB.User)
+	}
+	if cfg.HTTP.CacheTTL != 5*time.Minute || cfg.DB.Port != 5432 {
+		t.Fatalf("expected file values over defaults, got ttl %s port %d", cfg.HTTP.CacheTTL, cfg.DB.Port)
+	}
+	if strings.Join(cfg.Kafka.Brokers, ",") != "k1:9092,k2:9092" || !cfg.Telemetry.Enabled {
+		t.Fatalf("unexpected kafka/telemetry: %+v %+v", cfg.Kafka, cfg.Telemetry)
+	}
+}
+
+const minimalConfig = `
+[db]
+user = "user"
+database = "db"
+`
+
+func TestLoad_SecretFromFile(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+
+	cfg, _, err := Load([]string{"--config", writeConfig(t, minimalConfig)},
+		envMap(map[string]string{"WEB_DEMOSERVICE_DB_PASSWORD_FILE": secret}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DB.Password != "s3cret" {
+		t.Fatalf("expected password from file, got %q", cfg.DB.Password)
+	}
+
+	_, _, err = Load([]string{"--config", writeConfig(t, minimalConfig)}, envMap(map[string]string{
+		"WEB_DEMOSERVICE_DB_PASSWORD":      "a",
+		"WEB_DEMOSERVICE_DB_PASSWORD_FILE": secret,
+	}))
+	if err == nil {
+		t.Fatalf("expected error when both value and _FILE are set")
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, _, err := Load([]string{"--config", filepath.Join(t.TempDir(), "missing.toml")}, envMap(nil)); err == nil {
+		t.Fatalf("expected error for missing explicit config")
+	}
+
+	_, _, err := Load([]string{"--config", writeConfig(t, "")}, envMap(map[string]string{
+		"WEB_DEMOSERVICE_HTTP_PORT":      "http",
+		"WEB_DEMOSERVICE_HTTP_CACHE_TTL": "forever",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "WEB_DEMOSERVICE_HTTP_PORT") || !strings.Contains(err.Error(), "WEB_DEMOSERVICE_HTTP_CACHE_TTL") {
+		t.Fatalf("expected both env errors to be reported, got %v", err)
+	}
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	cfg := Defaults()
+	cfg.DB.Password = "s3cret"
+	cfg.Auth.APIKeys = []APIKeyConfig{{Name: "frontend", Hash: "sha256:abc"}}
+
+	var buf bytes.Buffer
+	if err := Print(&buf, &cfg); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "s3cret") || strings.Contains(out, "sha256:abc") || !strings.Contains(out, redacted) {
+		t.Fatalf("expected secrets to be redacted:\n%s", out)
+	}
+	if cfg.DB.Password != "s3cret" || cfg.Auth.APIKeys[0].Hash != "sha256:abc" {
+		t.Fatalf("print must not modify the config")
+	}
+}
+
+func TestHash(t *testing.T) {
+	a, b := Defaults(), Defaults()
+	if Hash(&a) == "" || Hash(&a) != Hash(&b) {
+		t.Fatalf("equal configs must have equal hashes")
+	}
+	b.DB.Password = "rotated"
+	if Hash(&a) == Hash(&b) {
+		t.Fatalf("setting a secret must change the hash")
+	}
+	a.DB.Password = "s3cret"
+	if Hash(&a) != Hash(&b) {
+		t.Fatalf("secret values must not affect the hash")
+	}
+	b.HTTP.Port++
+	if Hash(&a) == Hash(&b) {
+		t.Fatalf("setting change must change the hash")
+	}
+}
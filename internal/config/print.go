@@ -0,0 +1,58 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"reflect"
+
+	"github.com/BurntSushi/toml"
+)
+
+const redacted = "[redacted]"
+
+// Print пишет итоговый конфиг в TOML; поля с тегом secret:"true" заменяются на [redacted].
+func Print(w io.Writer, cfg *Config) error {
+	return toml.NewEncoder(w).Encode(withoutSecrets(cfg))
+}
+
+// withoutSecrets возвращает копию конфига без секретов.
+func withoutSecrets(cfg *Config) Config {
+	out := *cfg
+	redact(reflect.ValueOf(&out).Elem())
+	return out
+}
+
+// redact зачищает секреты в копии конфига; срезы структур копируются, чтобы не задеть оригинал.
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		fv := v.Field(i)
+		switch {
+		case field.Tag.Get("secret") == "true" && fv.Kind() == reflect.String:
+			if fv.String() != "" {
+				fv.SetString(redacted)
+			}
+		case fv.Kind() == reflect.Struct:
+			redact(fv)
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Struct:
+			items := reflect.MakeSlice(fv.Type(), fv.Len(), fv.Len())
+			reflect.Copy(items, fv)
+			for j := 0; j < items.Len(); j++ {
+				redact(items.Index(j))
+			}
+			fv.Set(items)
+		}
+	}
+}
+
+// Hash — sha256 итогового конфига в том виде, в каком его печатает Print: по нему сверяют,
+// что экземпляры работают с одинаковым конфигом. Секреты в хэш не входят, иначе по
+// опубликованному хэшу можно было бы перебором проверять догадки о них.
+func Hash(cfg *Config) string {
+	h := sha256.New()
+	if err := toml.NewEncoder(h).Encode(withoutSecrets(cfg)); err != nil {
+		return ""
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate проверяет конфиг целиком и возвращает все найденные проблемы одной ошибкой,
+// по строке на проблему вида "db.min_conns: must not exceed db.max_conns (10)".
+func (c *Config) Validate() error {
+	var v validator
+
+	v.port("http.port", c.HTTP.Port)
+	v.check(c.HTTP.CacheTTL >= time.Second, "http.cache_ttl", "must be at least 1s, got %s", c.HTTP.CacheTTL)
+	v.positive("http.read_header_timeout", c.HTTP.ReadHeaderTimeout)
+	v.positive("http.read_timeout", c.HTTP.ReadTimeout)
+	v.positive("http.write_timeout", c.HTTP.WriteTimeout)
+	v.positive("http.idle_timeout", c.HTTP.IdleTimeout)
+	v.positive("http.shutdown_timeout", c.HTTP.ShutdownTimeout)
+	v.check(c.HTTP.MaxHeaderBytes >= 1024, "http.max_header_bytes", "must be at least 1024, got %d", c.HTTP.MaxHeaderBytes)
+	v.check(c.HTTP.MaxBodyBytes >= 1, "http.max_body_bytes", "must be positive, got %d", c.HTTP.MaxBodyBytes)
+	v.tls("http.tls", c.HTTP.TLS)
+	v.check(!c.HTTP.CORS.AllowCredentials || !slices.Contains(c.HTTP.CORS.AllowedOrigins, "*"),
+		"http.cors.allow_credentials", "must not be combined with allowed_origins = [\"*\"]")
+	v.check(c.HTTP.CORS.MaxAge >= 0, "http.cors.max_age", "must not be negative, got %s", c.HTTP.CORS.MaxAge)
+	if c.HTTP.Admin.Enabled {
+		v.port("http.admin.port", c.HTTP.Admin.Port)
+		v.check(c.HTTP.Admin.Port != c.HTTP.Port, "http.admin.port", "must differ from http.port (%d)", c.HTTP.Port)
+		v.check(!c.GRPC.Enabled || c.HTTP.Admin.Port != c.GRPC.Port, "http.admin.port",
+			"must differ from grpc.port (%d)", c.GRPC.Port)
+		v.positive("http.admin.read_header_timeout", c.HTTP.Admin.ReadHeaderTimeout)
+		v.positive("http.admin.read_timeout", c.HTTP.Admin.ReadTimeout)
+		v.positive("http.admin.write_timeout", c.HTTP.Admin.WriteTimeout)
+		v.positive("http.admin.idle_timeout", c.HTTP.Admin.IdleTimeout)
+		v.tls("http.admin.tls", c.HTTP.Admin.TLS)
+	}
+
+	if c.GRPC.Enabled {
+		v.port("grpc.port", c.GRPC.Port)
+		v.check(c.GRPC.Port != c.HTTP.Port || c.GRPC.Host != c.HTTP.Host, "grpc.port",
+			"must differ from http.port (%d)", c.HTTP.Port)
+	}
+
+	if c.DB.URL == "" {
+		v.required("db.host", c.DB.Host)
+		v.port("db.port", c.DB.Port)
+		v.required("db.user", c.DB.User)
+		v.required("db.database", c.DB.Database)
+		v.check(slices.Contains(sslModes, c.DB.SSLMode), "db.sslmode",
+			"must be one of %s, got %q", strings.Join(sslModes, ", "), c.DB.SSLMode)
+	}
+	v.check(c.DB.MaxConns >= 1, "db.max_conns", "must be at least 1, got %d", c.DB.MaxConns)
+	v.check(c.DB.MinConns >= 0, "db.min_conns", "must not be negative, got %d", c.DB.MinConns)
+	v.check(c.DB.MinConns <= c.DB.MaxConns, "db.min_conns", "must not exceed db.max_conns (%d), got %d",
+		c.DB.MaxConns, c.DB.MinConns)
+	v.check(c.DB.MaxConnLifetime >= 0, "db.max_conn_lifetime", "must not be negative, got %s", c.DB.MaxConnLifetime)
+	// pgxpool заводит time.NewTicker на этот период, а он паникует на нуле.
+	v.positive("db.health_check_period", c.DB.HealthCheckPeriod)
+	v.retry("db.retry", c.DB.Retry)
+	if b := c.DB.Breaker; b.Enabled {
+		v.check(b.Window > 0, "db.breaker.window", "must be positive, got %s", b.Window)
+		v.check(b.MinRequests >= 1, "db.breaker.min_requests", "must be at least 1, got %d", b.MinRequests)
+		v.check(b.FailureRate > 0 && b.FailureRate <= 1, "db.breaker.failure_rate",
+			"must be in (0, 1], got %v", b.FailureRate)
+		v.check(b.OpenTimeout > 0, "db.breaker.open_timeout", "must be positive, got %s", b.OpenTimeout)
+		v.check(b.HalfOpenProbes >= 1, "db.breaker.half_open_probes", "must be at least 1, got %d", b.HalfOpenProbes)
+		v.check(b.CallTimeout >= 0, "db.breaker.call_timeout", "must not be negative, got %s", b.CallTimeout)
+	}
+
+	v.check(len(c.Kafka.Brokers) > 0, "kafka.brokers", "at least one broker is required")
+	for i, broker := range c.Kafka.Brokers {
+		v.check(strings.TrimSpace(broker) != "", fmt.Sprintf("kafka.brokers[%d]", i), "must not be empty")
+	}
+	v.required("kafka.topic", c.Kafka.Topic)
+	v.required("kafka.group_id", c.Kafka.GroupID)
+	v.required("kafka.dlq_topic", c.Kafka.DLQTopic)
+	v.check(c.Kafka.DLQTopic == "" || c.Kafka.DLQTopic != c.Kafka.Topic, "kafka.dlq_topic", "must differ from kafka.topic")
+	v.check(c.Kafka.LagInterval > 0, "kafka.lag_interval", "must be positive, got %s", c.Kafka.LagInterval)
+	v.retry("kafka.retry", c.Kafka.Retry)
+
+	if c.Telemetry.Enabled {
+		v.required("telemetry.service_name", c.Telemetry.ServiceName)
+		v.required("telemetry.otlp_endpoint", c.Telemetry.OTLPEndpoint)
+	}
+	v.check(c.Telemetry.SampleRatio >= 0 && c.Telemetry.SampleRatio <= 1, "telemetry.sample_ratio",
+		"must be between 0 and 1, got %v", c.Telemetry.SampleRatio)
+
+	if c.Metrics.Enabled {
+		v.check(strings.HasPrefix(c.Metrics.Path, "/"), "metrics.path", "must start with /, got %q", c.Metrics.Path)
+	}
+
+	if c.Auth.Enabled {
+		v.check(c.Auth.JWKSFile != "" || len(c.Auth.APIKeys) > 0, "auth",
+			"jwks_file or at least one api_keys entry is required when auth is enabled")
+		// Без issuer проверка iss пропускается и подходит токен любого издателя с тем же ключом.
+		if c.Auth.JWKSFile != "" {
+			v.required("auth.issuer", c.Auth.Issuer)
+		}
+	}
+	names := make(map[string]bool, len(c.Auth.APIKeys))
+	for i, key := range c.Auth.APIKeys {
+		path := fmt.Sprintf("auth.api_keys[%d]", i)
+		v.required(path+".name", key.Name)
+		v.check(key.Name == "" || !names[key.Name], path+".name", "duplicate name %q", key.Name)
+		names[key.Name] = true
+		v.check(validKeyHash(key.Hash), path+".hash", "must be sha256:<64 hex chars>")
+		v.check(len(key.Scopes) > 0, path+".scopes", "at least one scope is required")
+	}
+
+	if c.Encryption.Enabled {
+		v.required("encryption.keyfile", c.Encryption.Keyfile)
+	}
+	v.check(c.Encryption.RotationBatchSize >= 0, "encryption.rotation_batch_size", "must not be negative, got %d",
+		c.Encryption.RotationBatchSize)
+
+	if c.RateLimit.Enabled {
+		for _, name := range sortedKeys(c.RateLimit.Groups) {
+			g := c.RateLimit.Groups[name]
+			path := "rate_limit.groups." + name
+			v.check(g.Rate > 0, path+".rate", "must be positive, got %v", g.Rate)
+			v.check(g.Burst >= 1, path+".burst", "must be at least 1, got %d", g.Burst)
+		}
+	}
+
+	v.check(c.Audit.BufferSize >= 0, "audit.buffer_size", "must not be negative, got %d", c.Audit.BufferSize)
+	v.check(c.Audit.BatchSize >= 0, "audit.batch_size", "must not be negative, got %d", c.Audit.BatchSize)
+	v.check(c.Audit.BufferSize == 0 || c.Audit.BatchSize <= c.Audit.BufferSize, "audit.batch_size",
+		"must not exceed audit.buffer_size (%d), got %d", c.Audit.BufferSize, c.Audit.BatchSize)
+	v.check(c.Audit.FlushInterval >= 0, "audit.flush_interval", "must not be negative, got %s", c.Audit.FlushInterval)
+
+	var level slog.Level
+	v.check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level",
+		"must be debug, info, warn or error, got %q", c.Log.Level)
+	v.check(c.Log.Format == "text" || c.Log.Format == "json", "log.format", "must be text or json, got %q", c.Log.Format)
+	if c.Log.Sampling.Initial != 0 {
+		v.check(c.Log.Sampling.Initial > 0, "log.sampling.initial", "must not be negative, got %d", c.Log.Sampling.Initial)
+		v.check(c.Log.Sampling.Thereafter >= 0, "log.sampling.thereafter", "must not be negative, got %d", c.Log.Sampling.Thereafter)
+		v.check(c.Log.Sampling.Tick > 0, "log.sampling.tick", "must be positive, got %s", c.Log.Sampling.Tick)
+	}
+
+	return v.err()
+}
+
+type validator struct {
+	problems []error
+}
+
+func (v *validator) check(ok bool, path, format string, args ...any) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) required(path, value string) {
+	v.check(strings.TrimSpace(value) != "", path, "is required")
+}
+
+func (v *validator) port(path string, port int) {
+	v.check(port >= 1 && port <= 65535, path, "must be between 1 and 65535, got %d", port)
+}
+
+func (v *validator) positive(path string, d time.Duration) {
+	v.check(d > 0, path, "must be positive, got %s", d)
+}
+
+func (v *validator) retry(path string, r RetryConfig) {
+	v.check(r.Attempts >= 0, path+".attempts", "must not be negative, got %d", r.Attempts)
+	v.check(r.InitialBackoff > 0, path+".initial_backoff", "must be positive, got %s", r.InitialBackoff)
+	v.check(r.MaxBackoff >= r.InitialBackoff, path+".max_backoff", "must not be less than %s.initial_backoff (%s), got %s",
+		path, r.InitialBackoff, r.MaxBackoff)
+}
+
+func (v *validator) tls(path string, t TLSConfig) {
+	if !t.Enabled {
+		return
+	}
+	v.required(path+".cert_file", t.CertFile)
+	v.required(path+".key_file", t.KeyFile)
+	v.check(t.MinVersion == "1.2" || t.MinVersion == "1.3", path+".min_version",
+		"must be 1.2 or 1.3, got %q", t.MinVersion)
+}
+
+func (v *validator) err() error {
+	return errors.Join(v.problems...)
+}
+
+func validKeyHash(hash string) bool {
+	sum, ok := strings.CutPrefix(hash, "sha256:")
+	if !ok || len(sum) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(sum)
+	return err == nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() Config {
+	cfg := Defaults()
+	cfg.DB.User = "user"
+	cfg.DB.Database = "db"
+	return cfg
+}
+
+func TestValidate_Defaults(t *testing.T) {
+	cfg := validConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.HTTP.CacheTTL = time.Nanosecond
+	cfg.DB.MinConns = 20
+	cfg.DB.HealthCheckPeriod = 0
+	cfg.Kafka.Brokers = nil
+	cfg.Kafka.DLQTopic = ""
+	cfg.Telemetry.SampleRatio = 2
+	cfg.Auth.APIKeys = []APIKeyConfig{{Name: "frontend", Hash: "plain-key", Scopes: []string{"orders:read"}}}
+	cfg.RateLimit = RateLimitConfig{Enabled: true, Groups: map[string]RateLimitGroupConfig{"orders": {Rate: 0, Burst: 1}}}
+	cfg.Log.Format = "xml"
+	cfg.Kafka.Retry.MaxBackoff = cfg.Kafka.Retry.InitialBackoff / 2
+	cfg.DB.Breaker.FailureRate = 1.5
+	cfg.HTTP.WriteTimeout = 0
+	cfg.HTTP.CORS = CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{
+		"http.cache_ttl", "db.min_conns", "db.health_check_period", "kafka.brokers", "kafka.dlq_topic",
+		"telemetry.sample_ratio", "auth.api_keys[0].hash", "rate_limit.groups.orders.rate", "log.format",
+		"kafka.retry.max_backoff", "db.breaker.failure_rate",
+		"http.write_timeout", "http.cors.allow_credentials",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected problem with %s in:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoad_RejectsUnknownKeys(t *testing.T) {
+	path := writeConfig(t, minimalConfig+`
+[http]
+prot = 8080
+`)
+	_, _, err := Load([]string{"--config", path}, envMap(nil))
+	if err == nil || !strings.Contains(err.Error(), "http.prot: unknown key") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	prev := validConfig()
+	next := validConfig()
+	next.HTTP.CacheTTL *= 2
+	next.DB.Host = "other"
+	next.RateLimit.Groups = map[string]RateLimitGroupConfig{"orders": {Rate: 1, Burst: 1}}
+
+	if got := strings.Join(Diff(&prev, &next), ","); got != "http.cache_ttl,db.host,rate_limit.groups" {
+		t.Fatalf("unexpected diff: %s", got)
+	}
+}
+
+func TestValidate_JWTRequiresIssuer(t *testing.T) {
+	cfg := validConfig()
+	cfg.Auth.Enabled = true
+	cfg.Auth.JWKSFile = "keys/jwks.json"
+	cfg.Auth.Issuer = ""
+
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "auth.issuer") {
+		t.Fatalf("expected auth.issuer to be required with jwks_file, got %v", err)
+	}
+	cfg.Auth.Issuer = "https://issuer.example"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Действия в журнале аудита.
+const (
+	AuditActionOrderRead   = "order.read"
+	AuditActionOrderCreate = "order.create"
+	AuditActionAuditQuery  = "audit.query"
+	// Выгрузка и анонимизация данных клиента пишутся по событию на каждый заказ.
+	AuditActionCustomerExport = "customer.export"
+	AuditActionCustomerErase  = "customer.erase"
+	// Ручная очистка кэша через админский API.
+	AuditActionCacheEvict = "cache.evict"
+	AuditActionCacheFlush = "cache.flush"
+)
+
+// AuditEvent — запись журнала аудита. Журнал только дополняется.
+type AuditEvent struct {
+	ID         int64
+	OccurredAt time.Time
+	Actor      string
+	AuthMethod string
+	Action     string
+	OrderID    *uuid.UUID
+	Fields     []string
+	TraceID    string
+	Details    map[string]any
+}
+
+// AuditFilter — выборка журнала по заказу и/или актору, от новых к старым.
+// Before — id записи, с которой продолжить (не включая её).
+type AuditFilter struct {
+	OrderID *uuid.UUID
+	Actor   string
+	Before  int64
+	Limit   int
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CacheEntry — метаданные закэшированного заказа без самого заказа. Size — оценка в байтах.
+type CacheEntry struct {
+	OrderID    uuid.UUID
+	CreatedAt  time.Time
+	LastAccess time.Time
+	ExpiresAt  time.Time
+	Size       int
+	ETag       string
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomerExport — все заказы клиента для ответа на запрос доступа к данным.
+type CustomerExport struct {
+	CustomerID string
+	ExportedAt time.Time
+	Orders     []OrderWithInformation
+}
+
+// CustomerErasure — итог анонимизации: Anonymized меньше len(OrderIDs), если часть доставок
+// была анонимизирована раньше.
+type CustomerErasure struct {
+	CustomerID string
+	OrderIDs   []uuid.UUID
+	Anonymized int
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+// Ошибки хранилища, не зависящие от драйвера. Репозиторий оборачивает ими ошибки pgx,
+// сервис по ним строит типизированные ошибки.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrUnavailable = errors.New("storage unavailable")
+)
@@ -30,3 +30,25 @@ type OrderWithInformation struct {
 	Delivery Delivery
 	Payment  PaymentWithBank
 }
+
+// OrderCursor — позиция keyset-пагинации: заказы отдаются по убыванию (date_created, order_id).
+type OrderCursor struct {
+	DateCreated time.Time
+	ID          uuid.UUID
+}
+
+// Include — набор вложенных сущностей заказа, которые нужно загрузить.
+type Include uint8
+
+const (
+	IncludeDelivery Include = 1 << iota
+	IncludePayment
+	IncludeItems
+
+	IncludeNone Include = 0
+	IncludeAll          = IncludeDelivery | IncludePayment | IncludeItems
+)
+
+func (i Include) Has(part Include) bool {
+	return i&part == part
+}
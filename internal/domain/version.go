@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// OrderVersion — валидаторы представления заказа для условных запросов.
+type OrderVersion struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// NewOrderVersion считает strong ETag как хэш содержимого заказа. Заказ после
+// сохранения не меняется, поэтому Last-Modified — это date_created.
+func NewOrderVersion(order OrderWithInformation) OrderVersion {
+	version, _ := NewOrderVersionSize(order)
+	return version
+}
+
+// NewOrderVersionSize — NewOrderVersion плюс размер заказа в JSON, чтобы не кодировать его дважды.
+func NewOrderVersionSize(order OrderWithInformation) (OrderVersion, int) {
+	// json.Marshal доменной структуры детерминирован: поля в порядке объявления.
+	raw, _ := json.Marshal(order)
+	sum := sha256.Sum256(raw)
+
+	return OrderVersion{
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: order.DateCreated.UTC().Truncate(time.Second),
+	}, len(raw)
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecker_ReadyHandler(t *testing.T) {
+	gate := NewGate("cache warm-up in progress")
+	c := NewChecker(50 * time.Millisecond)
+	c.Add("postgres", func(context.Context) error { return nil })
+	c.Add("cache_warmup", gate.Check)
+
+	ready := func() (int, Report) {
+		rec := httptest.NewRecorder()
+		c.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var report Report
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("decode report: %v", err)
+		}
+		return rec.Code, report
+	}
+
+	code, report := ready()
+	if code != http.StatusServiceUnavailable || report.Status != StatusFail {
+		t.Fatalf("expected not ready before warm-up, got %d %+v", code, report)
+	}
+	if report.Checks[0].Name != "postgres" || report.Checks[0].Status != StatusOK ||
+		report.Checks[1].Error != "cache warm-up in progress" {
+		t.Fatalf("unexpected checks: %+v", report.Checks)
+	}
+
+	gate.Open()
+	if code, report = ready(); code != http.StatusOK || report.Status != StatusOK {
+		t.Fatalf("expected ready after warm-up, got %d %+v", code, report)
+	}
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	c := NewChecker(20 * time.Millisecond)
+	c.Add("kafka_brokers", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	c.Add("kafka_dlq", func(context.Context) error { return errors.New("topic orders_dlq not found") })
+
+	report := c.Run(context.Background())
+	if report.Status != StatusFail || report.Checks[0].Error != context.DeadlineExceeded.Error() ||
+		report.Checks[1].Status != StatusFail {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestLiveHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+}
@@ -0,0 +1,209 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const keySize = 32
+
+var ErrUnknownKey = errors.New("unknown master key")
+
+// Keyfile — формат локального файла с мастер-ключами. Новые ключи данных заворачиваются
+// активным ключом, остальные нужны только для чтения старых строк до ротации.
+// BlindIndexKey не ротируется: с ним считаются HMAC для поиска по email и телефону.
+type Keyfile struct {
+	ActiveKeyID   string            `json:"active_key_id"`
+	MasterKeys    map[string]string `json:"master_keys"`
+	BlindIndexKey string            `json:"blind_index_key"`
+}
+
+// Sealed — зашифрованное значение и ключ данных, завёрнутый мастер-ключом KeyID.
+type Sealed struct {
+	Ciphertext []byte
+	WrappedKey []byte
+	KeyID      string
+}
+
+type Keyring struct {
+	activeKeyID string
+	masters     map[string]cipher.AEAD
+	blindKey    []byte
+}
+
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keyfile %s: %w", path, err)
+	}
+
+	var kf Keyfile
+	if err = json.Unmarshal(data, &kf); err != nil {
+		return nil, fmt.Errorf("parse keyfile %s: %w", path, err)
+	}
+
+	return NewKeyring(kf)
+}
+
+func NewKeyring(kf Keyfile) (*Keyring, error) {
+	if _, ok := kf.MasterKeys[kf.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("active key %q is not in master_keys", kf.ActiveKeyID)
+	}
+
+	k := &Keyring{activeKeyID: kf.ActiveKeyID, masters: make(map[string]cipher.AEAD, len(kf.MasterKeys))}
+	for id, encoded := range kf.MasterKeys {
+		raw, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %q: %w", id, err)
+		}
+		if k.masters[id], err = newAEAD(raw); err != nil {
+			return nil, fmt.Errorf("master key %q: %w", id, err)
+		}
+	}
+
+	blindKey, err := decodeKey(kf.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+	k.blindKey = blindKey
+
+	return k, nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeKeyID
+}
+
+// Seal шифрует plaintext новым ключом данных; aad привязывает шифртекст к строке.
+func (k *Keyring) Seal(plaintext, aad []byte) (Sealed, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return Sealed{}, fmt.Errorf("generate data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return Sealed{}, err
+	}
+	ciphertext, err := seal(aead, plaintext, aad)
+	if err != nil {
+		return Sealed{}, err
+	}
+
+	wrapped, err := seal(k.masters[k.activeKeyID], dataKey, []byte(k.activeKeyID))
+	if err != nil {
+		return Sealed{}, fmt.Errorf("wrap data key: %w", err)
+	}
+
+	return Sealed{Ciphertext: ciphertext, WrappedKey: wrapped, KeyID: k.activeKeyID}, nil
+}
+
+func (k *Keyring) Open(s Sealed, aad []byte) ([]byte, error) {
+	dataKey, err := k.unwrap(s)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(aead, s.Ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Rewrap заворачивает ключ данных активным мастер-ключом; шифртекст не меняется.
+func (k *Keyring) Rewrap(s Sealed) (Sealed, error) {
+	if s.KeyID == k.activeKeyID {
+		return s, nil
+	}
+
+	dataKey, err := k.unwrap(s)
+	if err != nil {
+		return Sealed{}, err
+	}
+	wrapped, err := seal(k.masters[k.activeKeyID], dataKey, []byte(k.activeKeyID))
+	if err != nil {
+		return Sealed{}, fmt.Errorf("wrap data key: %w", err)
+	}
+
+	return Sealed{Ciphertext: s.Ciphertext, WrappedKey: wrapped, KeyID: k.activeKeyID}, nil
+}
+
+// BlindIndex — детерминированный HMAC-SHA256 для поиска по точному совпадению.
+// Нормализация значения — забота вызывающего.
+func (k *Keyring) BlindIndex(value string) []byte {
+	mac := hmac.New(sha256.New, k.blindKey)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func (k *Keyring) unwrap(s Sealed) ([]byte, error) {
+	master, ok := k.masters[s.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, s.KeyID)
+	}
+
+	dataKey, err := open(master, s.WrappedKey, []byte(s.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// GenerateKey возвращает новый случайный ключ в формате Keyfile.
+func GenerateKey() (string, error) {
+	raw := make([]byte, keySize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64: %w", err)
+	}
+	if len(raw) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(raw))
+	}
+	return raw, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal возвращает nonce || ciphertext.
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
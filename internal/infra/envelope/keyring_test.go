@@ -0,0 +1,108 @@
+package envelope
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func newTestKeyfile(t *testing.T, ids ...string) Keyfile {
+	t.Helper()
+
+	kf := Keyfile{ActiveKeyID: ids[len(ids)-1], MasterKeys: make(map[string]string)}
+	for _, id := range ids {
+		key, err := GenerateKey()
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		kf.MasterKeys[id] = key
+	}
+
+	var err error
+	if kf.BlindIndexKey, err = GenerateKey(); err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return kf
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	k, err := NewKeyring(newTestKeyfile(t, "k1"))
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+
+	sealed, err := k.Seal([]byte("secret"), []byte("row-1"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if sealed.KeyID != "k1" || bytes.Contains(sealed.Ciphertext, []byte("secret")) {
+		t.Fatalf("unexpected sealed value: %+v", sealed)
+	}
+
+	plaintext, err := k.Open(sealed, []byte("row-1"))
+	if err != nil || string(plaintext) != "secret" {
+		t.Fatalf("expected secret, got %q, %v", plaintext, err)
+	}
+
+	if _, err = k.Open(sealed, []byte("row-2")); err == nil {
+		t.Fatalf("expected error when ciphertext is moved to another row")
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	kf := newTestKeyfile(t, "k1")
+	old, err := NewKeyring(kf)
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	sealed, err := old.Seal([]byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	rotated := newTestKeyfile(t, "k2")
+	rotated.MasterKeys["k1"] = kf.MasterKeys["k1"]
+	rotated.BlindIndexKey = kf.BlindIndexKey
+	k, err := NewKeyring(rotated)
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+
+	rewrapped, err := k.Rewrap(sealed)
+	if err != nil {
+		t.Fatalf("rewrap: %v", err)
+	}
+	if rewrapped.KeyID != "k2" || !bytes.Equal(rewrapped.Ciphertext, sealed.Ciphertext) {
+		t.Fatalf("expected only the data key to be rewrapped, got %+v", rewrapped)
+	}
+	if plaintext, err := k.Open(rewrapped, nil); err != nil || string(plaintext) != "secret" {
+		t.Fatalf("expected secret, got %q, %v", plaintext, err)
+	}
+
+	// После удаления старого ключа из файла непереобёрнутые строки не читаются.
+	delete(rotated.MasterKeys, "k1")
+	withoutOld, err := NewKeyring(rotated)
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	if _, err = withoutOld.Open(sealed, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+	if !bytes.Equal(withoutOld.BlindIndex("a@b.c"), old.BlindIndex("a@b.c")) {
+		t.Fatalf("blind index must survive master key rotation")
+	}
+}
+
+func TestNewKeyring_Validates(t *testing.T) {
+	kf := newTestKeyfile(t, "k1")
+	kf.ActiveKeyID = "missing"
+	if _, err := NewKeyring(kf); err == nil {
+		t.Fatalf("expected error for unknown active key")
+	}
+
+	kf = newTestKeyfile(t, "k1")
+	kf.MasterKeys["k1"] = "c2hvcnQ="
+	if _, err := NewKeyring(kf); err == nil {
+		t.Fatalf("expected error for short key")
+	}
+}
@@ -3,27 +3,189 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+	"web_demoservice/internal/telemetry"
 
+	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
 type Consumer struct {
-	client *kgo.Client
+	client  *kgo.Client
+	groupID string
+	topic   string
+
+	mu       sync.Mutex
+	assigned map[string][]int32
 }
 
+// NewConsumer: коммитятся только offset'ы, отмеченные MarkDone, — запись, которую не успели
+// обработать до ребаланса или остановки, будет прочитана снова.
 func NewConsumer(brokers []string, groupID, topic string) (*Consumer, error) {
+	c := &Consumer{groupID: groupID, topic: topic, assigned: make(map[string][]int32)}
+
 	client, err := kgo.NewClient(
 		kgo.SeedBrokers(brokers...),
 		kgo.ConsumerGroup(groupID),
-		kgo.ConsumeTopics(topic))
+		kgo.ConsumeTopics(topic),
+		kgo.AutoCommitMarks(),
+		kgo.OnPartitionsAssigned(c.onAssigned),
+		kgo.OnPartitionsRevoked(c.onRevoked),
+		kgo.OnPartitionsLost(c.onLost))
 
 	if err != nil {
 		return nil, fmt.Errorf("new client: %w", err)
 	}
 
-	return &Consumer{client: client}, nil
+	c.client = client
+	return c, nil
 }
 
 func (c *Consumer) Fetch(ctx context.Context) kgo.Fetches {
-	return c.client.PollFetches(ctx)
+	fetches := c.client.PollFetches(ctx)
+	if n := fetches.NumRecords(); n > 0 {
+		telemetry.ObserveKafkaFetchBatch(n)
+	}
+	return fetches
+}
+
+// MarkDone отмечает запись обработанной (сохранена или отправлена в DLQ); её offset уйдёт
+// в ближайший автокоммит.
+func (c *Consumer) MarkDone(record *kgo.Record) {
+	c.client.MarkCommitRecords(record)
+}
+
+// Pause останавливает получение записей топика; уже полученные записи не теряются.
+func (c *Consumer) Pause() {
+	c.client.PauseFetchTopics(c.topic)
+	telemetry.SetKafkaPaused(true)
+}
+
+func (c *Consumer) Resume() {
+	c.client.ResumeFetchTopics(c.topic)
+	telemetry.SetKafkaPaused(false)
+}
+
+// CheckBrokers проверяет, что брокер отвечает на запрос метаданных и топик существует.
+func (c *Consumer) CheckBrokers(ctx context.Context) error {
+	return checkTopic(ctx, c.client, c.topic)
+}
+
+// CheckMembership проверяет, что консьюмер состоит в группе (прошёл join и sync).
+func (c *Consumer) CheckMembership(context.Context) error {
+	memberID, generation := c.client.GroupMetadata()
+	if memberID == "" || generation < 0 {
+		return fmt.Errorf("not a member of consumer group %s", c.groupID)
+	}
+	return nil
+}
+
+// Close коммитит отмеченные offset'ы, выходит из группы и закрывает клиента. Вызывается
+// после того, как Fetch больше не вызывается и обработка текущих записей закончена.
+func (c *Consumer) Close(ctx context.Context) error {
+	err := c.client.CommitMarkedOffsets(ctx)
+	if err != nil {
+		err = fmt.Errorf("commit offsets: %w", err)
+	}
+	c.client.Close()
+	return err
+}
+
+// Assignment возвращает партиции, назначенные этому инстансу, по топикам.
+func (c *Consumer) Assignment() map[string][]int32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[string][]int32, len(c.assigned))
+	for topic, partitions := range c.assigned {
+		out[topic] = append([]int32(nil), partitions...)
+	}
+	return out
+}
+
+// RunLagMonitor раз в interval запрашивает у брокера лаг группы и обновляет kafka_consumer_lag.
+func (c *Consumer) RunLagMonitor(ctx context.Context, interval time.Duration) {
+	admin := kadm.NewClient(c.client)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.reportLag(ctx, admin)
+		}
+	}
+}
+
+func (c *Consumer) reportLag(ctx context.Context, admin *kadm.Client) {
+	lags, err := admin.Lag(ctx, c.groupID)
+	if err == nil {
+		err = lags.Error()
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to query kafka consumer lag", slog.Any("error", err))
+		return
+	}
+
+	telemetry.ResetKafkaLag()
+	for _, lag := range lags[c.groupID].Lag.Sorted() {
+		if lag.Err != nil || lag.Lag < 0 {
+			continue
+		}
+		telemetry.SetKafkaLag(lag.Topic, lag.Partition, lag.Lag)
+	}
+}
+
+func (c *Consumer) onAssigned(_ context.Context, _ *kgo.Client, assigned map[string][]int32) {
+	telemetry.IncKafkaRebalance("assigned")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for topic, partitions := range assigned {
+		merged := append(c.assigned[topic], partitions...)
+		sort.Slice(merged, func(i, j int) bool { return merged[i] < merged[j] })
+		c.assigned[topic] = merged
+	}
+}
+
+// onRevoked успевает закоммитить отмеченные offset'ы, пока партиции ещё наши.
+func (c *Consumer) onRevoked(ctx context.Context, client *kgo.Client, revoked map[string][]int32) {
+	telemetry.IncKafkaRebalance("revoked")
+	if err := client.CommitMarkedOffsets(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to commit offsets on revoke", slog.Any("error", err))
+	}
+	c.unassign(revoked)
+}
+
+func (c *Consumer) onLost(_ context.Context, _ *kgo.Client, lost map[string][]int32) {
+	telemetry.IncKafkaRebalance("lost")
+	c.unassign(lost)
+}
+
+func (c *Consumer) unassign(partitions map[string][]int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for topic, removed := range partitions {
+		drop := make(map[int32]bool, len(removed))
+		for _, p := range removed {
+			drop[p] = true
+		}
+		kept := c.assigned[topic][:0]
+		for _, p := range c.assigned[topic] {
+			if !drop[p] {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == 0 {
+			delete(c.assigned, topic)
+		} else {
+			c.assigned[topic] = kept
+		}
+	}
 }
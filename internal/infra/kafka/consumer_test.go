@@ -0,0 +1,28 @@
+package kafka
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestConsumer_Assignment(t *testing.T) {
+	c := &Consumer{assigned: make(map[string][]int32)}
+	ctx := context.Background()
+
+	c.onAssigned(ctx, nil, map[string][]int32{"orders": {2, 0}})
+	c.onAssigned(ctx, nil, map[string][]int32{"orders": {1}})
+	if got := c.Assignment(); !reflect.DeepEqual(got, map[string][]int32{"orders": {0, 1, 2}}) {
+		t.Fatalf("unexpected assignment after assign: %v", got)
+	}
+
+	c.onLost(ctx, nil, map[string][]int32{"orders": {1}})
+	if got := c.Assignment(); !reflect.DeepEqual(got, map[string][]int32{"orders": {0, 2}}) {
+		t.Fatalf("unexpected assignment after lost: %v", got)
+	}
+
+	c.onLost(ctx, nil, map[string][]int32{"orders": {0, 2}})
+	if got := c.Assignment(); len(got) != 0 {
+		t.Fatalf("expected empty assignment, got %v", got)
+	}
+}
@@ -0,0 +1,25 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// checkTopic запрашивает метаданные топика: брокер отвечает и топик существует.
+func checkTopic(ctx context.Context, client *kgo.Client, topic string) error {
+	topics, err := kadm.NewClient(client).ListTopics(ctx, topic)
+	if err != nil {
+		return fmt.Errorf("kafka metadata: %w", err)
+	}
+	detail, ok := topics[topic]
+	if !ok {
+		return fmt.Errorf("kafka topic %s not found", topic)
+	}
+	if detail.Err != nil {
+		return fmt.Errorf("kafka topic %s: %w", topic, detail.Err)
+	}
+	return nil
+}
@@ -11,13 +11,32 @@ import (
 type Producer struct {
 	client recordProducer
 	topic  string
+	redact func(string) string
+	// redactValue очищает тело записи; без него значение уходит в DLQ как есть.
+	redactValue func([]byte) []byte
+}
+
+type ProducerOption func(*Producer)
+
+// WithErrorRedactor очищает текст ошибки в заголовке dlq_error (например, от PII).
+func WithErrorRedactor(redact func(string) string) ProducerOption {
+	return func(p *Producer) {
+		p.redact = redact
+	}
+}
+
+// WithValueRedactor очищает тело записи перед отправкой в DLQ (например, маскирует доставку).
+func WithValueRedactor(redact func([]byte) []byte) ProducerOption {
+	return func(p *Producer) {
+		p.redactValue = redact
+	}
 }
 
 type recordProducer interface {
 	Produce(ctx context.Context, record *kgo.Record, fn func(*kgo.Record, error))
 }
 
-func NewProducer(brokers []string, topic string) (*Producer, error) {
+func NewProducer(brokers []string, topic string, opts ...ProducerOption) (*Producer, error) {
 	client, err := kgo.NewClient(
 		kgo.SeedBrokers(brokers...),
 	)
@@ -25,10 +44,10 @@ func NewProducer(brokers []string, topic string) (*Producer, error) {
 		return nil, fmt.Errorf("new client: %w", err)
 	}
 
-	return newProducerWithClient(client, topic)
+	return newProducerWithClient(client, topic, opts...)
 }
 
-func newProducerWithClient(client recordProducer, topic string) (*Producer, error) {
+func newProducerWithClient(client recordProducer, topic string, opts ...ProducerOption) (*Producer, error) {
 	if topic == "" {
 		return nil, fmt.Errorf("dlq topic is required")
 	}
@@ -36,7 +55,35 @@ func newProducerWithClient(client recordProducer, topic string) (*Producer, erro
 		return nil, fmt.Errorf("producer client is required")
 	}
 
-	return &Producer{client: client, topic: topic}, nil
+	p := &Producer{client: client, topic: topic}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
+}
+
+// Close дожидается отправки буферизованных записей и закрывает клиента.
+func (p *Producer) Close(ctx context.Context) error {
+	client, ok := p.client.(*kgo.Client)
+	if !ok {
+		return nil
+	}
+	err := client.Flush(ctx)
+	if err != nil {
+		err = fmt.Errorf("flush dlq producer: %w", err)
+	}
+	client.Close()
+	return err
+}
+
+// Check проверяет, что брокер доступен и DLQ-топик существует.
+func (p *Producer) Check(ctx context.Context) error {
+	client, ok := p.client.(*kgo.Client)
+	if !ok {
+		return nil
+	}
+	return checkTopic(ctx, client, p.topic)
 }
 
 func (p *Producer) Publish(ctx context.Context, src *kgo.Record, cause error) error {
@@ -47,10 +94,20 @@ func (p *Producer) Publish(ctx context.Context, src *kgo.Record, cause error) er
 		return fmt.Errorf("nil error")
 	}
 
+	errText := cause.Error()
+	if p.redact != nil {
+		errText = p.redact(errText)
+	}
+
+	value := src.Value
+	if p.redactValue != nil && len(value) > 0 {
+		value = p.redactValue(value)
+	}
+
 	headers := make([]kgo.RecordHeader, 0, len(src.Headers)+5)
 	headers = append(headers, src.Headers...)
 	headers = append(headers,
-		kgo.RecordHeader{Key: "dlq_error", Value: []byte(cause.Error())},
+		kgo.RecordHeader{Key: "dlq_error", Value: []byte(errText)},
 		kgo.RecordHeader{Key: "dlq_source_topic", Value: []byte(src.Topic)},
 		kgo.RecordHeader{Key: "dlq_source_partition", Value: []byte(fmt.Sprintf("%d", src.Partition))},
 		kgo.RecordHeader{Key: "dlq_source_offset", Value: []byte(fmt.Sprintf("%d", src.Offset))},
@@ -60,7 +117,7 @@ func (p *Producer) Publish(ctx context.Context, src *kgo.Record, cause error) er
 	record := &kgo.Record{
 		Topic:   p.topic,
 		Key:     src.Key,
-		Value:   src.Value,
+		Value:   value,
 		Headers: headers,
 	}
 
@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -103,3 +104,45 @@ func TestProducer_Publish_AddsDLQHeaders(t *testing.T) {
 		t.Fatalf("expected dlq_ts header to be set")
 	}
 }
+
+func TestProducer_Publish_RedactsError(t *testing.T) {
+	fp := &fakeProducer{}
+	p, err := newProducerWithClient(fp, "dlq", WithErrorRedactor(func(s string) string {
+		return strings.ReplaceAll(s, "secret@mail.ru", "***")
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := p.Publish(context.Background(), &kgo.Record{Topic: "source"}, errors.New("duplicate secret@mail.ru")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, h := range fp.produced[0].Headers {
+		if h.Key == "dlq_error" && string(h.Value) != "duplicate ***" {
+			t.Fatalf("expected redacted dlq_error, got %q", h.Value)
+		}
+	}
+}
+
+func TestProducer_Publish_RedactsValue(t *testing.T) {
+	fp := &fakeProducer{}
+	p, err := newProducerWithClient(fp, "dlq", WithValueRedactor(func(v []byte) []byte {
+		return []byte(strings.ReplaceAll(string(v), "secret@mail.ru", "***"))
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src := &kgo.Record{Topic: "source", Value: []byte(`{"email":"secret@mail.ru"}`)}
+	if err := p.Publish(context.Background(), src, errors.New("boom")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := string(fp.produced[0].Value); got != `{"email":"***"}` {
+		t.Fatalf("expected redacted value, got %q", got)
+	}
+	if string(src.Value) != `{"email":"secret@mail.ru"}` {
+		t.Fatalf("source record must not be modified")
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"web_demoservice/internal/config"
+	"web_demoservice/internal/telemetry"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// NewPostgresPool создаёт пул без проверки соединения: соединения открываются лениво,
+// доступность БД при старте проверяет вызывающий (с повторами).
 func NewPostgresPool(cfg config.PostgresConfig, ctx context.Context) (*pgxpool.Pool, error) {
 	dsn := cfg.DSN()
 
@@ -20,15 +23,13 @@ func NewPostgresPool(cfg config.PostgresConfig, ctx context.Context) (*pgxpool.P
 	poolConfig.MinConns = cfg.MinConns
 	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
 	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	poolConfig.ConnConfig.Tracer = QueryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
 
-	if err := pool.Ping(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
-	}
-
+	telemetry.ObserveDBPool(pool)
 	return pool, nil
 }
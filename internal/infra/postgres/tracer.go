@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+	"web_demoservice/internal/telemetry"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const queryNamePrefix = "-- name:"
+
+// QueryName — стабильное имя запроса для метрик и спанов: из комментария "-- name: get_items"
+// в начале SQL, иначе первое ключевое слово ("begin", "commit") или текст служебного
+// комментария ("-- ping" у pgx).
+func QueryName(sql string) string {
+	var comment string
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, queryNamePrefix):
+			return strings.TrimSpace(strings.TrimPrefix(line, queryNamePrefix))
+		case strings.HasPrefix(line, "--"):
+			if comment == "" {
+				comment = strings.TrimSpace(strings.TrimPrefix(line, "--"))
+			}
+			continue
+		}
+		keyword, _, _ := strings.Cut(line, " ")
+		return strings.ToLower(strings.TrimRight(keyword, ";"))
+	}
+	if comment != "" {
+		return comment
+	}
+	return "unknown"
+}
+
+// QueryTracer превращает каждый запрос и батч в спан с db.statement и наблюдение
+// db_query_duration_seconds по имени запроса.
+type QueryTracer struct{}
+
+type traceState struct {
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+type traceStateKey struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return startTrace(ctx, QueryName(data.SQL), data.SQL)
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	endTrace(ctx, data.Err)
+}
+
+func (QueryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	name, statement := "batch", ""
+	if data.Batch != nil && len(data.Batch.QueuedQueries) > 0 {
+		statement = data.Batch.QueuedQueries[0].SQL
+		name = QueryName(statement)
+	}
+	ctx = startTrace(ctx, name, statement)
+	if st, ok := ctx.Value(traceStateKey{}).(*traceState); ok && data.Batch != nil {
+		st.span.SetAttributes(attribute.Int("db.batch.size", data.Batch.Len()))
+	}
+	return ctx
+}
+
+func (QueryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	if st, ok := ctx.Value(traceStateKey{}).(*traceState); ok && data.Err != nil {
+		st.span.RecordError(data.Err)
+	}
+}
+
+func (QueryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	endTrace(ctx, data.Err)
+}
+
+func startTrace(ctx context.Context, name, statement string) context.Context {
+	ctx, span := otel.Tracer("postgres").Start(ctx, "db."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", name),
+			attribute.String("db.statement", strings.TrimSpace(statement)),
+		),
+	)
+	return context.WithValue(ctx, traceStateKey{}, &traceState{name: name, start: time.Now(), span: span})
+}
+
+func endTrace(ctx context.Context, err error) {
+	st, ok := ctx.Value(traceStateKey{}).(*traceState)
+	if !ok {
+		return
+	}
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+		st.span.RecordError(err)
+		st.span.SetStatus(codes.Error, err.Error())
+	}
+	st.span.End()
+	telemetry.ObserveDBQuery(st.name, result, time.Since(st.start))
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import "testing"
+
+func TestQueryName(t *testing.T) {
+	tests := map[string]string{
+		"\n\t\t-- name: get_items\n\t\tSELECT 1\n":                         "get_items",
+		"-- name: insert_bank\nINSERT INTO banks.banks (name) VALUES ($1)": "insert_bank",
+		"begin":   "begin",
+		"COMMIT;": "commit",
+		"-- ping": "ping",
+		"":        "unknown",
+	}
+	for sql, want := range tests {
+		if got := QueryName(sql); got != want {
+			t.Errorf("QueryName(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"web_demoservice/internal/config"
+)
+
+// NewHandler собирает handler из конфига: формат, уровень level (может меняться на лету через
+// slog.LevelVar), семплирование и атрибуты из контекста (trace_id, span_id, order_id, request_id).
+func NewHandler(w io.Writer, cfg config.LogConfig, level slog.Leveler) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch cfg.Format {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	if cfg.Sampling.Initial > 0 {
+		handler = NewSamplingHandler(handler, cfg.Sampling.Initial, cfg.Sampling.Thereafter, cfg.Sampling.Tick)
+	}
+	return NewContextHandler(handler), nil
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"web_demoservice/internal/config"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewHandler_JSONWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := NewHandler(&buf, config.LogConfig{Format: "json"}, slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = WithOrderID(ctx, "order-1")
+	ctx = WithRequestID(ctx, "req-1")
+
+	slog.New(handler).InfoContext(ctx, "hello")
+	slog.New(handler).DebugContext(ctx, "hidden")
+
+	var entry map[string]any
+	if err = json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one json line, got %q: %v", buf.String(), err)
+	}
+	want := map[string]string{
+		"msg":        "hello",
+		"trace_id":   sc.TraceID().String(),
+		"span_id":    sc.SpanID().String(),
+		"order_id":   "order-1",
+		"request_id": "req-1",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Fatalf("expected %s=%s, got %v", key, value, entry[key])
+		}
+	}
+}
+
+func TestNewHandler_UnknownFormat(t *testing.T) {
+	if _, err := NewHandler(&bytes.Buffer{}, config.LogConfig{Format: "xml"}, slog.LevelInfo); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
+
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSamplingHandler(slog.NewTextHandler(&buf, nil), 2, 3, time.Second)
+	now := time.Unix(0, 0)
+	h.sampler.now = func() time.Time { return now }
+	logger := slog.New(h)
+
+	for i := 0; i < 8; i++ {
+		logger.Info("noisy")
+	}
+	logger.Error("failure")
+	logger.Error("failure")
+
+	// Первые 2 и затем каждая 3-я: записи 1, 2, 5, 8.
+	if got := strings.Count(buf.String(), "msg=noisy"); got != 4 {
+		t.Fatalf("expected 4 sampled records, got %d", got)
+	}
+	if got := strings.Count(buf.String(), "msg=failure"); got != 2 {
+		t.Fatalf("errors must not be sampled, got %d", got)
+	}
+
+	now = now.Add(time.Second)
+	buf.Reset()
+	logger.Info("noisy")
+	if !strings.Contains(buf.String(), "msg=noisy") {
+		t.Fatalf("expected counters reset after tick")
+	}
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SamplingHandler гасит повторяющиеся записи: за каждый tick одинаковых записей (уровень и
+// сообщение) пропускаются первые initial, затем каждая thereafter-я (0 — ни одной).
+// Записи уровня error и выше не семплируются.
+type SamplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+func NewSamplingHandler(next slog.Handler, initial, thereafter int, tick time.Duration) *SamplingHandler {
+	return &SamplingHandler{
+		next: next,
+		sampler: &sampler{
+			initial:    initial,
+			thereafter: thereafter,
+			tick:       tick,
+			now:        time.Now,
+			counts:     make(map[sampleKey]int),
+		},
+	}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < slog.LevelError && !h.sampler.allow(record.Level, record.Message) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs и WithGroup делят счётчики с исходным handler: лимит общий на процесс.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+type sampleKey struct {
+	level   slog.Level
+	message string
+}
+
+type sampler struct {
+	initial    int
+	thereafter int
+	tick       time.Duration
+	now        func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+}
+
+func (s *sampler) allow(level slog.Level, message string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.windowStart) >= s.tick {
+		s.windowStart = now
+		clear(s.counts)
+	}
+
+	key := sampleKey{level: level, message: message}
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
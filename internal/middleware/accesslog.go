@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+	"web_demoservice/internal/auth"
+
+	"github.com/gorilla/mux"
+)
+
+type accessEntryKey struct{}
+
+// accessEntry заполняется ниже по цепочке: клиент становится известен только в
+// Authenticate, который стоит на подроутерах.
+type accessEntry struct {
+	identity *auth.Identity
+}
+
+// recordIdentity сообщает access log клиента запроса; без AccessLog ничего не делает.
+func recordIdentity(ctx context.Context, identity *auth.Identity) {
+	if entry, ok := ctx.Value(accessEntryKey{}).(*accessEntry); ok {
+		entry.identity = identity
+	}
+}
+
+// AccessLog пишет одну запись на запрос: маршрут (шаблон mux), статус, размер ответа,
+// длительность, User-Agent и клиента. request_id и trace_id добавляет обработчик логов
+// из контекста, поэтому AccessLog должен стоять после RequestID.
+func AccessLog(trustForwardedFor bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			entry := &accessEntry{}
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			ctx := context.WithValue(r.Context(), accessEntryKey{}, entry)
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			route := ""
+			if current := mux.CurrentRoute(r); current != nil {
+				route, _ = current.GetPathTemplate()
+			}
+			attrs := []any{
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Int64("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_ip", clientIP(r, trustForwardedFor)),
+				slog.String("user_agent", r.UserAgent()),
+			}
+			if entry.identity != nil {
+				attrs = append(attrs,
+					slog.String("caller", entry.identity.Subject),
+					slog.String("auth_method", string(entry.identity.Method)))
+			}
+
+			level := slog.LevelInfo
+			if rec.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			slog.Log(r.Context(), level, "http request", attrs...)
+		})
+	}
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = statusCode, true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap нужен http.ResponseController (Flush, дедлайны) за обёрткой.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
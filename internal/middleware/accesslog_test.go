@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/logging"
+
+	"github.com/gorilla/mux"
+)
+
+// captureLogs подменяет логгер по умолчанию JSON-логгером с request_id из контекста.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(logging.NewContextHandler(slog.NewJSONHandler(&buf, nil))))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if seen != "req-42" || rec.Header().Get(RequestIDHeader) != "req-42" {
+		t.Fatalf("expected incoming id to be kept, got ctx %q, header %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if seen == "" || strings.Contains(seen, " ") || rec.Header().Get(RequestIDHeader) != seen {
+		t.Fatalf("expected generated id for invalid header, got %q", seen)
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	buf := captureLogs(t)
+
+	router := mux.NewRouter()
+	router.Use(RequestID, AccessLog(false))
+	sub := router.PathPrefix("/api/v1").Subrouter()
+	sub.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recordIdentity(r.Context(), &auth.Identity{Subject: "frontend", Method: auth.MethodAPIKey})
+			next.ServeHTTP(w, r)
+		})
+	})
+	sub.HandleFunc("/order/{order_id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/42", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	req.Header.Set("User-Agent", "curl/8.0")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	records := decodeRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected one access log line, got %d", len(records))
+	}
+	want := map[string]any{
+		"msg":        "http request",
+		"route":      "/api/v1/order/{order_id}",
+		"status":     float64(200),
+		"bytes":      float64(5),
+		"user_agent": "curl/8.0",
+		"caller":     "frontend",
+		"request_id": "req-1",
+	}
+	for key, value := range want {
+		if records[0][key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, records[0][key])
+		}
+	}
+}
+
+func TestPanicCover_LogsStackAndRequestID(t *testing.T) {
+	buf := captureLogs(t)
+
+	h := RequestID(PanicCover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/42", nil)
+	req.Header.Set(RequestIDHeader, "req-2")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	record := decodeRecords(t, buf)[0]
+	stack, _ := record["stack"].(string)
+	if record["request_id"] != "req-2" || !strings.Contains(stack, "TestPanicCover_LogsStackAndRequestID") {
+		t.Fatalf("expected request id and stack in panic log, got %v", record)
+	}
+}
+
+func TestPanicCover_AbortAndStartedResponse(t *testing.T) {
+	captureLogs(t)
+
+	h := PanicCover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Fatalf("expected ErrAbortHandler to be re-panicked, got %v", p)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	h = PanicCover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("partial"))
+		panic("boom")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Fatalf("started response must not be overwritten, got %d %q", rec.Code, rec.Body.String())
+	}
+}
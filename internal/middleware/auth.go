@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// Authenticate пропускает только запросы с валидным X-API-Key или Authorization: Bearer
+// и кладёт клиента в контекст запроса.
+func Authenticate(a *auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, err := a.Authenticate(r.Header.Get("Authorization"), r.Header.Get(APIKeyHeader))
+			if err != nil {
+				if !errors.Is(err, auth.ErrNoCredentials) {
+					slog.Warn("authentication failed", slog.String("path", r.URL.Path), slog.Any("error", err))
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer realm="web_demoservice"`)
+				problem.Write(w, r, service.Unauthenticated(service.CodeUnauthenticated, "valid API key or bearer token required", err))
+				return
+			}
+
+			recordIdentity(r.Context(), identity)
+			trace.SpanFromContext(r.Context()).SetAttributes(
+				attribute.String("enduser.id", identity.Subject),
+				attribute.String("enduser.auth_method", string(identity.Method)),
+			)
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// RequireScope должен стоять после Authenticate.
+func RequireScope(scope auth.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity := auth.FromContext(r.Context())
+			if !identity.HasScope(scope) {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, scope))
+				problem.Write(w, r, service.Forbidden(service.CodeInsufficientScope, "scope "+scope+" required"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/config"
+)
+
+func newTestAuthHandler(t *testing.T, scope auth.Scope) http.Handler {
+	t.Helper()
+
+	a, err := auth.NewAuthenticator(config.AuthConfig{
+		APIKeys: []config.APIKeyConfig{
+			{Name: "reader", Hash: auth.HashAPIKey("reader-key"), Scopes: []string{auth.ScopeOrdersRead}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new authenticator: %v", err)
+	}
+
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if identity := auth.FromContext(r.Context()); identity == nil || identity.Subject != "reader" {
+			t.Fatalf("expected reader identity in context, got %+v", identity)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return Authenticate(a)(RequireScope(scope)(final))
+}
+
+func TestAuthenticate(t *testing.T) {
+	cases := []struct {
+		name   string
+		scope  auth.Scope
+		key    string
+		status int
+	}{
+		{"no credentials", auth.ScopeOrdersRead, "", http.StatusUnauthorized},
+		{"wrong key", auth.ScopeOrdersRead, "wrong", http.StatusUnauthorized},
+		{"missing scope", auth.ScopeOrdersReadPII, "reader-key", http.StatusForbidden},
+		{"ok", auth.ScopeOrdersRead, "reader-key", http.StatusNoContent},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/order/x", nil)
+		if tc.key != "" {
+			req.Header.Set(APIKeyHeader, tc.key)
+		}
+		rec := httptest.NewRecorder()
+
+		newTestAuthHandler(t, tc.scope).ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.status, rec.Code)
+		}
+		if tc.status != http.StatusNoContent && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Fatalf("%s: expected WWW-Authenticate header", tc.name)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
+)
+
+// MaxBody ограничивает тело запроса limit байтами. Запрос с известным Content-Length сверх
+// лимита сразу получает 413; иначе чтение тела обрывается на лимите с *http.MaxBytesError.
+func MaxBody(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				problem.Write(w, r, service.TooLarge(service.CodeRequestTooLarge,
+					fmt.Sprintf("request body must not exceed %d bytes", limit)))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
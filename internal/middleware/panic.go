@@ -1,14 +1,36 @@
 package middleware
 
-import "net/http"
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"web_demoservice/internal/transport/http/problem"
+)
 
+// PanicCover превращает панику в 500 и логирует её со стеком; request_id попадает в запись
+// из контекста. http.ErrAbortHandler пробрасывается дальше: им хэндлер сам обрывает ответ.
+// Если ответ уже начат, второй заголовок не пишется — клиент получит оборванное тело.
 func PanicCover(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
 		defer func() {
-			if err := recover(); err != nil {
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			err := recover()
+			if err == nil {
+				return
+			}
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+			slog.ErrorContext(r.Context(), "panic recovered",
+				slog.Any("panic", err),
+				slog.String("path", r.URL.Path),
+				slog.Bool("response_started", rec.wroteHeader),
+				slog.String("stack", string(debug.Stack())))
+			if !rec.wroteHeader {
+				problem.Write(w, r, fmt.Errorf("panic: %v", err))
 			}
 		}()
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(rec, r)
 	})
 }
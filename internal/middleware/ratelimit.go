@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/ratelimit"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/telemetry"
+	"web_demoservice/internal/transport/http/problem"
+)
+
+// RateLimit ограничивает группу маршрутов group. Ключ — клиент из контекста (ставится
+// Authenticate, поэтому RateLimit должен стоять после него), иначе IP. X-Forwarded-For
+// учитывается, только если trustForwardedFor: иначе клиент подделает себе новый ключ.
+func RateLimit(limiter *ratelimit.Limiter, group string, trustForwardedFor bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := "ip:" + clientIP(r, trustForwardedFor)
+			if identity := auth.FromContext(r.Context()); identity != nil {
+				key = "id:" + identity.Subject
+			}
+
+			res, limited, err := limiter.Allow(r.Context(), group, key)
+			if err != nil {
+				// Недоступный стор не должен ронять API.
+				slog.Warn("rate limiter failed, request allowed", slog.String("group", group), slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !limited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			if !res.Allowed {
+				telemetry.IncThrottled(group)
+				w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+				problem.Write(w, r, service.RateLimited(service.CodeRateLimited, "rate limit exceeded, retry later"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP: при trustForwardedFor берётся правый адрес X-Forwarded-For — его дописал наш
+// прокси. Левые адреса присылает клиент, и по ним он получал бы новую корзину на каждый запрос.
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			xff := strings.Join(values, ",")
+			last := strings.TrimSpace(xff[strings.LastIndex(xff, ",")+1:])
+			if net.ParseIP(last) != nil {
+				return last
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
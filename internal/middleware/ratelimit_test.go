@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter, err := ratelimit.New(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{"orders": {Rate: 1, Burst: 2}})
+	if err != nil {
+		t.Fatalf("new limiter: %v", err)
+	}
+	h := RateLimit(limiter, "orders", false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	do := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/order/x", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		return req
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := do(newReq()); rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: expected %d, got %d", i, http.StatusNoContent, rec.Code)
+		}
+	}
+
+	rec := do(newReq())
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "1" || rec.Header().Get("RateLimit-Remaining") != "0" || rec.Header().Get("RateLimit-Limit") != "2" {
+		t.Fatalf("unexpected rate limit headers: %v", rec.Header())
+	}
+
+	// Клиент с API ключом лимитируется отдельно от своего IP.
+	req := newReq()
+	req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{Subject: "frontend"}))
+	if rec := do(req); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected identity to have its own bucket, got %d", rec.Code)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.2")
+
+	if got := clientIP(req, false); got != "10.0.0.1" {
+		t.Fatalf("expected remote addr without trusted proxy, got %s", got)
+	}
+	if got := clientIP(req, true); got != "10.0.0.2" {
+		t.Fatalf("expected address appended by the proxy, got %s", got)
+	}
+
+	// Клиент подставляет свой X-Forwarded-For, прокси дописывает реальный адрес.
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	req.Header.Add("X-Forwarded-For", "203.0.113.7")
+	if got := clientIP(req, true); got != "203.0.113.7" {
+		t.Fatalf("spoofed entries must be ignored, got %s", got)
+	}
+
+	req.Header.Set("X-Forwarded-For", "not-an-ip")
+	if got := clientIP(req, true); got != "10.0.0.1" {
+		t.Fatalf("expected remote addr for malformed header, got %s", got)
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"web_demoservice/internal/logging"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID: чужой ID принимается, только если он короткий и без спецсимволов —
+// он попадает в логи и заголовки ответа.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID берёт X-Request-ID запроса или генерирует новый, возвращает его в ответе и
+// кладёт в контекст (логи) и в атрибуты спана. Должен стоять после трейсинга.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.request_id", id))
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,67 @@
+package pii
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogHandler маскирует персональные данные в сообщении и атрибутах записи: атрибуты
+// с именем поля delivery — по правилу поля, остальные строки и ошибки — как текст.
+type LogHandler struct {
+	next   slog.Handler
+	masker *Masker
+}
+
+func NewLogHandler(next slog.Handler, masker *Masker) *LogHandler {
+	return &LogHandler{next: next, masker: masker}
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	masked := slog.NewRecord(record.Time, record.Level, h.masker.Text(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		masked.AddAttrs(h.attr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, masked)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		masked[i] = h.attr(attr)
+	}
+	return &LogHandler{next: h.next.WithAttrs(masked), masker: h.masker}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name), masker: h.masker}
+}
+
+func (h *LogHandler) attr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindString:
+		if fn, ok := h.masker.fields[attr.Key]; ok {
+			return slog.String(attr.Key, fn(value.String()))
+		}
+		return slog.String(attr.Key, h.masker.Text(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		masked := make([]any, len(group))
+		for i, a := range group {
+			masked[i] = h.attr(a)
+		}
+		return slog.Group(attr.Key, masked...)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, h.masker.Text(err.Error()))
+		}
+	}
+
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,215 @@
+package pii
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"web_demoservice/internal/domain"
+)
+
+const mask = "***"
+
+// Правила маскирования:
+//
+//	none              — значение не меняется;
+//	full              — значение целиком заменяется на "***";
+//	email             — первая буква логина и домен: j***@mail.ru;
+//	edges:<N>:<M>     — первые N и последние M символов: edges:2:2 → +7***45.
+const (
+	RuleNone  = "none"
+	RuleFull  = "full"
+	RuleEmail = "email"
+	ruleEdges = "edges"
+)
+
+// DefaultRules — правила для полей delivery, если в конфиге не задано иное.
+var DefaultRules = map[string]string{
+	"name":    "edges:1:0",
+	"phone":   "edges:2:2",
+	"email":   RuleEmail,
+	"address": RuleFull,
+}
+
+// deliveryFields — поля delivery (json-имена), для которых можно задать правило.
+var deliveryFields = map[string]func(d *domain.Delivery, fn maskFunc){
+	"name":    func(d *domain.Delivery, fn maskFunc) { d.Name = fn(d.Name) },
+	"phone":   func(d *domain.Delivery, fn maskFunc) { d.Phone = fn(d.Phone) },
+	"zip":     func(d *domain.Delivery, fn maskFunc) { d.Zip = fn(d.Zip) },
+	"city":    func(d *domain.Delivery, fn maskFunc) { d.City = fn(d.City) },
+	"address": func(d *domain.Delivery, fn maskFunc) { d.Address = fn(d.Address) },
+	"region": func(d *domain.Delivery, fn maskFunc) {
+		if d.Region != nil {
+			region := fn(*d.Region)
+			d.Region = &region
+		}
+	},
+	"email": func(d *domain.Delivery, fn maskFunc) { d.Email = fn(d.Email) },
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+\d[\d\-\s()]{7,}\d`)
+)
+
+type maskFunc func(string) string
+
+// Masker маскирует персональные данные в заказах и произвольном тексте (логи, ошибки).
+type Masker struct {
+	fields map[string]maskFunc
+	// В свободном тексте email и телефоны маскируются всегда, даже если для поля задан none.
+	textEmail maskFunc
+	textPhone maskFunc
+}
+
+// NewMasker накладывает rules поверх DefaultRules. Неизвестное поле или правило — ошибка.
+func NewMasker(rules map[string]string) (*Masker, error) {
+	merged := make(map[string]string, len(DefaultRules)+len(rules))
+	for field, rule := range DefaultRules {
+		merged[field] = rule
+	}
+	for field, rule := range rules {
+		merged[field] = rule
+	}
+
+	m := &Masker{
+		fields:    make(map[string]maskFunc, len(merged)),
+		textEmail: maskEmail,
+		textPhone: maskEdges(2, 2),
+	}
+	for field, rule := range merged {
+		if _, ok := deliveryFields[field]; !ok {
+			return nil, fmt.Errorf("pii rule for unknown delivery field %q", field)
+		}
+		fn, err := parseRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("pii rule for %s: %w", field, err)
+		}
+		m.fields[field] = fn
+
+		if rule == RuleNone {
+			continue
+		}
+		switch field {
+		case "email":
+			m.textEmail = fn
+		case "phone":
+			m.textPhone = fn
+		}
+	}
+
+	return m, nil
+}
+
+// Order возвращает копию заказа с замаскированной доставкой.
+func (m *Masker) Order(order *domain.OrderWithInformation) *domain.OrderWithInformation {
+	masked := *order
+	masked.Delivery = m.Delivery(order.Delivery)
+	return &masked
+}
+
+// Delivery возвращает замаскированную копию; исходное значение (например, из кэша) не меняется.
+func (m *Masker) Delivery(d domain.Delivery) domain.Delivery {
+	for field, fn := range m.fields {
+		deliveryFields[field](&d, fn)
+	}
+	return d
+}
+
+// Field маскирует значение поля delivery по его правилу; для полей без правила — как есть.
+func (m *Masker) Field(field, value string) string {
+	if fn, ok := m.fields[field]; ok {
+		return fn(value)
+	}
+	return value
+}
+
+// Text маскирует email и телефоны, встретившиеся в свободном тексте.
+func (m *Masker) Text(s string) string {
+	s = emailPattern.ReplaceAllStringFunc(s, m.textEmail)
+	return phonePattern.ReplaceAllStringFunc(s, m.textPhone)
+}
+
+// Payload маскирует поля delivery в JSON-сообщении заказа (например, перед отправкой в DLQ),
+// а email и телефоны в остальных полях — как в Text. Не-JSON обрабатывается как текст.
+func (m *Masker) Payload(value []byte) []byte {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(value, &doc); err != nil {
+		return []byte(m.Text(string(value)))
+	}
+
+	if raw, ok := doc["delivery"]; ok {
+		var delivery map[string]any
+		if err := json.Unmarshal(raw, &delivery); err == nil && delivery != nil {
+			for field, v := range delivery {
+				if s, ok := v.(string); ok {
+					delivery[field] = m.Field(field, s)
+				}
+			}
+			if masked, err := json.Marshal(delivery); err == nil {
+				doc["delivery"] = masked
+			}
+		} else {
+			doc["delivery"] = json.RawMessage(`"` + mask + `"`)
+		}
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return []byte(m.Text(string(value)))
+	}
+	return []byte(m.Text(string(out)))
+}
+
+func parseRule(rule string) (maskFunc, error) {
+	switch rule {
+	case RuleNone:
+		return func(s string) string { return s }, nil
+	case RuleFull:
+		return maskFull, nil
+	case RuleEmail:
+		return maskEmail, nil
+	}
+
+	parts := strings.Split(rule, ":")
+	if len(parts) != 3 || parts[0] != ruleEdges {
+		return nil, fmt.Errorf("unknown rule %q", rule)
+	}
+	prefix, err := strconv.Atoi(parts[1])
+	if err != nil || prefix < 0 {
+		return nil, fmt.Errorf("invalid prefix length in %q", rule)
+	}
+	suffix, err := strconv.Atoi(parts[2])
+	if err != nil || suffix < 0 {
+		return nil, fmt.Errorf("invalid suffix length in %q", rule)
+	}
+
+	return maskEdges(prefix, suffix), nil
+}
+
+func maskFull(s string) string {
+	if s == "" {
+		return s
+	}
+	return mask
+}
+
+func maskEdges(prefix, suffix int) maskFunc {
+	return func(s string) string {
+		runes := []rune(s)
+		// Короткое значение раскрылось бы почти целиком — скрываем полностью.
+		if len(runes) <= prefix+suffix {
+			return maskFull(s)
+		}
+		return string(runes[:prefix]) + mask + string(runes[len(runes)-suffix:])
+	}
+}
+
+func maskEmail(s string) string {
+	local, domainPart, ok := strings.Cut(s, "@")
+	if !ok || local == "" {
+		return maskFull(s)
+	}
+	return maskEdges(1, 0)(local) + "@" + domainPart
+}
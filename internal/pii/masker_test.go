@@ -0,0 +1,141 @@
+package pii
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"web_demoservice/internal/domain"
+)
+
+func TestMasker_DefaultRules(t *testing.T) {
+	m, err := NewMasker(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	region := "Moscow"
+	src := domain.Delivery{
+		Name:    "Ivan Ivanov",
+		Phone:   "+79001234545",
+		Zip:     "101000",
+		City:    "Moscow",
+		Address: "Tverskaya 1",
+		Region:  &region,
+		Email:   "john@mail.ru",
+	}
+
+	got := m.Delivery(src)
+
+	if got.Phone != "+7***45" {
+		t.Fatalf("expected +7***45, got %s", got.Phone)
+	}
+	if got.Email != "j***@mail.ru" {
+		t.Fatalf("expected j***@mail.ru, got %s", got.Email)
+	}
+	if got.Name != "I***" || got.Address != "***" {
+		t.Fatalf("unexpected name/address: %s / %s", got.Name, got.Address)
+	}
+	if got.City != "Moscow" || got.Zip != "101000" || *got.Region != "Moscow" {
+		t.Fatalf("fields without rule must be kept, got %+v", got)
+	}
+	if src.Phone != "+79001234545" || src.Email != "john@mail.ru" {
+		t.Fatalf("source delivery must not be modified")
+	}
+}
+
+func TestMasker_ConfiguredRules(t *testing.T) {
+	m, err := NewMasker(map[string]string{"phone": "edges:0:4", "name": "none", "region": "full"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	region := "Moscow"
+	got := m.Delivery(domain.Delivery{Name: "Ivan", Phone: "+79001234545", Region: &region})
+	if got.Phone != "***4545" || got.Name != "Ivan" || *got.Region != "***" {
+		t.Fatalf("unexpected masking: %+v", got)
+	}
+	if region != "Moscow" {
+		t.Fatalf("source region must not be modified")
+	}
+}
+
+func TestNewMasker_RejectsInvalidRules(t *testing.T) {
+	for _, rules := range []map[string]string{
+		{"passport": "full"},
+		{"phone": "partial"},
+		{"phone": "edges:2"},
+		{"phone": "edges:-1:2"},
+	} {
+		if _, err := NewMasker(rules); err == nil {
+			t.Errorf("%v: expected error", rules)
+		}
+	}
+}
+
+func TestMasker_ShortValuesAreFullyMasked(t *testing.T) {
+	m, _ := NewMasker(nil)
+	if got := m.Field("phone", "+71"); got != "***" {
+		t.Fatalf("expected ***, got %s", got)
+	}
+	if got := m.Field("phone", ""); got != "" {
+		t.Fatalf("expected empty value to stay empty, got %q", got)
+	}
+}
+
+func TestMasker_Text(t *testing.T) {
+	m, _ := NewMasker(map[string]string{"email": "none"})
+
+	got := m.Text("duplicate key (email)=(john@mail.ru), phone +7 900 123-45-45, order 550e8400-e29b-41d4-a716-446655440000")
+	want := "duplicate key (email)=(j***@mail.ru), phone +7***45, order 550e8400-e29b-41d4-a716-446655440000"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestLogHandler_MasksAttributes(t *testing.T) {
+	m, _ := NewMasker(nil)
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil), m))
+
+	logger.With(slog.String("email", "john@mail.ru")).Error(
+		"save failed for john@mail.ru",
+		slog.String("phone", "+79001234545"),
+		slog.Any("error", errors.New("conflict on john@mail.ru")),
+		slog.Group("delivery", slog.String("name", "Ivan")),
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "john@mail.ru") || strings.Contains(out, "+79001234545") || strings.Contains(out, "Ivan") {
+		t.Fatalf("PII leaked into log: %s", out)
+	}
+	if !strings.Contains(out, "j***@mail.ru") || !strings.Contains(out, "+7***45") {
+		t.Fatalf("expected masked values in log: %s", out)
+	}
+}
+
+func TestMasker_Payload(t *testing.T) {
+	m, err := NewMasker(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src := `{"order_uid":"b563feb7","delivery":{"name":"Ivan Ivanov","phone":"+79001234545","email":"john@mail.ru","address":"Tverskaya 1","city":"Moscow"},"customer_id":"john@mail.ru"}`
+	got := string(m.Payload([]byte(src)))
+
+	for _, raw := range []string{"Ivan Ivanov", "+79001234545", "john@mail.ru", "Tverskaya 1"} {
+		if strings.Contains(got, raw) {
+			t.Fatalf("expected %q masked, got %s", raw, got)
+		}
+	}
+	for _, kept := range []string{`"order_uid":"b563feb7"`, `"city":"Moscow"`, `"phone":"+7***45"`} {
+		if !strings.Contains(got, kept) {
+			t.Fatalf("expected %s in payload, got %s", kept, got)
+		}
+	}
+
+	if got := string(m.Payload([]byte("broken john@mail.ru"))); got != "broken j***@mail.ru" {
+		t.Fatalf("expected non-json payload masked as text, got %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limit — параметры token bucket: Rate токенов в секунду, не больше Burst в запасе.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter — через сколько появится следующий токен (для отказа).
+	RetryAfter time.Duration
+	// Reset — через сколько бакет снова будет полным.
+	Reset time.Duration
+}
+
+// Store хранит бакеты. Реализация в памяти годится для одного инстанса; для нескольких
+// инстансов нужен общий стор (например, Redis) с той же семантикой.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Limiter применяет лимиты групп маршрутов к ключам клиентов.
+type Limiter struct {
+	store Store
+	now   func() time.Time
+
+	mu     sync.RWMutex
+	groups map[string]Limit
+}
+
+func New(store Store, groups map[string]Limit) (*Limiter, error) {
+	l := &Limiter{store: store, now: time.Now}
+	if err := l.SetLimits(groups); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// SetLimits заменяет лимиты групп; бакеты клиентов сохраняются.
+func (l *Limiter) SetLimits(groups map[string]Limit) error {
+	copied := make(map[string]Limit, len(groups))
+	for name, limit := range groups {
+		if limit.Rate <= 0 || limit.Burst < 1 {
+			return fmt.Errorf("rate limit group %q: rate must be positive and burst at least 1", name)
+		}
+		copied[name] = limit
+	}
+
+	l.mu.Lock()
+	l.groups = copied
+	l.mu.Unlock()
+	return nil
+}
+
+// Allow забирает токен клиента key в группе group. Группа без лимита не ограничивается.
+func (l *Limiter) Allow(ctx context.Context, group, key string) (Result, bool, error) {
+	l.mu.RLock()
+	limit, ok := l.groups[group]
+	l.mu.RUnlock()
+	if !ok {
+		return Result{}, false, nil
+	}
+
+	res, err := l.store.Take(ctx, group+"|"+key, limit, l.now())
+	if err != nil {
+		return Result{}, true, fmt.Errorf("take token: %w", err)
+	}
+	return res, true, nil
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	s := NewMemoryStore()
+	limit := Limit{Rate: 2, Burst: 3}
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 3; i++ {
+		res, _ := s.Take(context.Background(), "k", limit, now)
+		if !res.Allowed {
+			t.Fatalf("request %d: expected burst to be allowed", i)
+		}
+		if res.Remaining != 2-i {
+			t.Fatalf("request %d: expected remaining %d, got %d", i, 2-i, res.Remaining)
+		}
+	}
+
+	res, _ := s.Take(context.Background(), "k", limit, now)
+	if res.Allowed {
+		t.Fatalf("expected request over burst to be rejected")
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("expected retry after 500ms, got %s", res.RetryAfter)
+	}
+	if res.Reset != 1500*time.Millisecond {
+		t.Fatalf("expected reset in 1.5s, got %s", res.Reset)
+	}
+
+	res, _ = s.Take(context.Background(), "k", limit, now.Add(500*time.Millisecond))
+	if !res.Allowed {
+		t.Fatalf("expected a token to be refilled after 500ms")
+	}
+
+	if res, _ = s.Take(context.Background(), "other", limit, now); !res.Allowed {
+		t.Fatalf("keys must not share a bucket")
+	}
+}
+
+func TestMemoryStore_Cleanup(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Unix(1000, 0)
+	_, _ = s.Take(context.Background(), "old", Limit{Rate: 1, Burst: 1}, now)
+	_, _ = s.Take(context.Background(), "fresh", Limit{Rate: 1, Burst: 1}, now.Add(time.Hour))
+
+	s.cleanup(now.Add(time.Hour), time.Minute)
+
+	if _, ok := s.buckets["old"]; ok {
+		t.Fatalf("expected idle bucket to be removed")
+	}
+	if _, ok := s.buckets["fresh"]; !ok {
+		t.Fatalf("expected fresh bucket to be kept")
+	}
+}
+
+func TestLimiter_Groups(t *testing.T) {
+	l, err := New(NewMemoryStore(), map[string]Limit{"orders": {Rate: 1, Burst: 1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, limited, _ := l.Allow(context.Background(), "public", "ip:1"); limited {
+		t.Fatalf("group without limit must not be limited")
+	}
+
+	res, limited, _ := l.Allow(context.Background(), "orders", "ip:1")
+	if !limited || !res.Allowed {
+		t.Fatalf("expected first request to be allowed, got %+v", res)
+	}
+	if res, _, _ = l.Allow(context.Background(), "orders", "ip:1"); res.Allowed {
+		t.Fatalf("expected second request to be rejected")
+	}
+
+	if err = l.SetLimits(map[string]Limit{"orders": {Rate: 0, Burst: 1}}); err == nil {
+		t.Fatalf("expected error for zero rate")
+	}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"web_demoservice/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func NewAuditPostgresRepository(db *pgxpool.Pool) *AuditPostgresRepository {
+	return &AuditPostgresRepository{db: db}
+}
+
+type AuditPostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+// Append пишет пачку событий одним батчем.
+func (r *AuditPostgresRepository) Append(ctx context.Context, events []domain.AuditEvent) error {
+	const q = `
+		-- name: insert_audit_event
+		INSERT INTO audit.events
+		    (occurred_at, actor, auth_method, action, order_id, fields, trace_id, details)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		var details []byte
+		if len(e.Details) > 0 {
+			var err error
+			if details, err = json.Marshal(e.Details); err != nil {
+				return fmt.Errorf("marshal audit details: %w", err)
+			}
+		}
+		batch.Queue(q, e.OccurredAt, e.Actor, nullString(e.AuthMethod), e.Action, e.OrderID, e.Fields,
+			nullString(e.TraceID), details)
+	}
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert audit events: %w", mapError(err))
+	}
+	return nil
+}
+
+// Query возвращает события по фильтру, от новых к старым.
+func (r *AuditPostgresRepository) Query(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if filter.OrderID != nil {
+		where = append(where, "order_id = "+arg(*filter.OrderID))
+	}
+	if filter.Actor != "" {
+		where = append(where, "actor = "+arg(filter.Actor))
+	}
+	if filter.Before > 0 {
+		where = append(where, "id < "+arg(filter.Before))
+	}
+
+	q := `
+		-- name: query_audit_events
+		SELECT id, occurred_at, actor, COALESCE(auth_method, ''), action, order_id, fields,
+		       COALESCE(trace_id, ''), details
+		FROM audit.events`
+	if len(where) > 0 {
+		q += "\n\t\tWHERE " + strings.Join(where, " AND ")
+	}
+	q += "\n\t\tORDER BY id DESC\n\t\tLIMIT " + arg(filter.Limit)
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit events: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	var events []domain.AuditEvent
+	for rows.Next() {
+		var (
+			e       domain.AuditEvent
+			details []byte
+		)
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.AuthMethod, &e.Action, &e.OrderID, &e.Fields,
+			&e.TraceID, &details); err != nil {
+			return nil, fmt.Errorf("scan audit event: %w", err)
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, fmt.Errorf("unmarshal audit details: %w", err)
+			}
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate audit events: %w", mapError(err))
+	}
+
+	return events, nil
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,55 @@
+//go:build integration
+// +build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"web_demoservice/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditPostgresRepository_AppendAndQuery(t *testing.T) {
+	pool := newTestPool(t)
+	ctx := context.Background()
+
+	var table *string
+	if err := pool.QueryRow(ctx, "SELECT to_regclass('audit.events')").Scan(&table); err != nil || table == nil {
+		t.Skip("audit schema not migrated")
+	}
+
+	repo := NewAuditPostgresRepository(pool)
+	orderID := uuid.New()
+	actor := "test-" + uuid.NewString()
+	err := repo.Append(ctx, []domain.AuditEvent{
+		{OccurredAt: time.Now(), Actor: actor, AuthMethod: "api_key", Action: domain.AuditActionOrderRead,
+			OrderID: &orderID, Fields: []string{"*"}, Details: map[string]any{"pii_masked": true}},
+		{OccurredAt: time.Now(), Actor: actor, Action: domain.AuditActionAuditQuery},
+	})
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	byOrder, err := repo.Query(ctx, domain.AuditFilter{OrderID: &orderID, Limit: 10})
+	if err != nil {
+		t.Fatalf("query by order: %v", err)
+	}
+	if len(byOrder) != 1 || byOrder[0].Actor != actor || byOrder[0].Fields[0] != "*" || byOrder[0].Details["pii_masked"] != true {
+		t.Fatalf("unexpected events by order: %+v", byOrder)
+	}
+
+	byActor, err := repo.Query(ctx, domain.AuditFilter{Actor: actor, Limit: 10})
+	if err != nil {
+		t.Fatalf("query by actor: %v", err)
+	}
+	if len(byActor) != 2 || byActor[0].ID <= byActor[1].ID {
+		t.Fatalf("expected two events newest first, got %+v", byActor)
+	}
+
+	if _, err = pool.Exec(ctx, "DELETE FROM audit.events WHERE actor = $1", actor); err == nil {
+		t.Fatalf("expected audit.events to reject deletes")
+	}
+}
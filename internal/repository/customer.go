@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// FindOrderIDsByCustomer возвращает заказы клиента от старых к новым.
+func (r *OrderPostgresRepository) FindOrderIDsByCustomer(ctx context.Context, customerID string) ([]uuid.UUID, error) {
+	const q = `
+		-- name: find_orders_by_customer
+		SELECT order_id FROM orders.orders
+		WHERE customer_id = $1
+		ORDER BY date_created, order_id
+	`
+	return r.findOrderIDs(ctx, q, customerID)
+}
+
+// AnonymizeDeliveries обнуляет PII доставки заказов (открытые колонки, шифротекст и blind
+// index). zip, city и region, как и заказ, оплата и товары, остаются. Возвращает число
+// анонимизированных строк; уже анонимизированные не считаются.
+func (r *OrderPostgresRepository) AnonymizeDeliveries(ctx context.Context, orderIDs []uuid.UUID) (int, error) {
+	const q = `
+		-- name: anonymize_deliveries
+		UPDATE orders.delivery
+		SET name = NULL, phone = NULL, address = NULL, email = NULL,
+		    pii_ciphertext = NULL, pii_data_key = NULL, pii_key_id = NULL,
+		    email_bidx = NULL, phone_bidx = NULL, anonymized_at = NOW()
+		WHERE order_id = ANY($1) AND anonymized_at IS NULL
+	`
+	tag, err := r.db.Exec(ctx, q, orderIDs)
+	if err != nil {
+		return 0, fmt.Errorf("anonymize delivery: %w", mapError(err))
+	}
+	return int(tag.RowsAffected()), nil
+}
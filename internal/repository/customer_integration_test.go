@@ -0,0 +1,59 @@
+//go:build integration
+// +build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestOrderPostgresRepository_AnonymizeDeliveries(t *testing.T) {
+	pool := newTestPool(t)
+	ctx := context.Background()
+
+	var column *string
+	if err := pool.QueryRow(ctx, `SELECT column_name FROM information_schema.columns
+		WHERE table_schema = 'orders' AND table_name = 'delivery' AND column_name = 'anonymized_at'`).Scan(&column); err != nil {
+		t.Skip("delivery anonymization is not migrated")
+	}
+
+	repo := NewOrderPostgresRepository(pool, mustKeyring(t, testKeyfile(t, "k1", nil)))
+	order := sampleOrder(uuid.New())
+	order.CustomerID = "gdpr-" + uuid.NewString()
+	cleanupOrder(t, pool, order)
+
+	if _, err := repo.Create(ctx, order); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	ids, err := repo.FindOrderIDsByCustomer(ctx, order.CustomerID)
+	if err != nil || len(ids) != 1 || ids[0] != order.ID {
+		t.Fatalf("expected customer order %s, got %v (%v)", order.ID, ids, err)
+	}
+
+	n, err := repo.AnonymizeDeliveries(ctx, ids)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one anonymized delivery, got %d (%v)", n, err)
+	}
+	if n, _ = repo.AnonymizeDeliveries(ctx, ids); n != 0 {
+		t.Fatalf("expected repeated anonymization to be a no-op, got %d", n)
+	}
+
+	got, err := repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("get order: %v", err)
+	}
+	if got.Delivery.Name != "" || got.Delivery.Email != "" || got.Delivery.Phone != "" || got.Delivery.Address != "" {
+		t.Fatalf("expected delivery pii to be erased, got %+v", got.Delivery)
+	}
+	if got.Delivery.City != order.Delivery.City || got.Payment.Amount != order.Payment.Amount || len(got.Items) != 1 {
+		t.Fatalf("expected non-pii and financial data to be kept, got %+v", got)
+	}
+
+	if byEmail, _ := repo.FindOrderIDsByEmail(ctx, order.Delivery.Email); len(byEmail) != 0 {
+		t.Fatalf("expected blind index to be erased, got %v", byEmail)
+	}
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/infra/envelope"
+
+	"github.com/google/uuid"
+)
+
+// deliveryPII — поля доставки, которые хранятся только в зашифрованном виде.
+type deliveryPII struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+	Email   string `json:"email"`
+}
+
+// deliveryRow — строка orders.delivery: открытые колонки PII заполнены только у строк,
+// записанных без шифрования.
+type deliveryRow struct {
+	id         int64
+	orderID    uuid.UUID
+	name       *string
+	phone      *string
+	address    *string
+	email      *string
+	ciphertext []byte
+	dataKey    []byte
+	keyID      *string
+}
+
+// deliveryPIIArgs возвращает значения колонок name, phone, address, email, pii_ciphertext,
+// pii_data_key, pii_key_id, email_bidx, phone_bidx.
+func (r *OrderPostgresRepository) deliveryPIIArgs(orderID uuid.UUID, d domain.Delivery) ([]any, error) {
+	if r.keyring == nil {
+		return []any{d.Name, d.Phone, d.Address, d.Email, nil, nil, nil, nil, nil}, nil
+	}
+
+	plaintext, err := json.Marshal(deliveryPII{Name: d.Name, Phone: d.Phone, Address: d.Address, Email: d.Email})
+	if err != nil {
+		return nil, fmt.Errorf("marshal delivery pii: %w", err)
+	}
+	sealed, err := r.keyring.Seal(plaintext, orderID[:])
+	if err != nil {
+		return nil, fmt.Errorf("encrypt delivery pii: %w", err)
+	}
+
+	return []any{
+		nil, nil, nil, nil,
+		sealed.Ciphertext, sealed.WrappedKey, sealed.KeyID,
+		r.keyring.BlindIndex(normalizeEmail(d.Email)), r.keyring.BlindIndex(normalizePhone(d.Phone)),
+	}, nil
+}
+
+func (r *OrderPostgresRepository) openDelivery(row deliveryRow, delivery *domain.Delivery) error {
+	if row.ciphertext == nil {
+		delivery.Name, delivery.Phone = deref(row.name), deref(row.phone)
+		delivery.Address, delivery.Email = deref(row.address), deref(row.email)
+		return nil
+	}
+	if r.keyring == nil {
+		return fmt.Errorf("delivery of order %s is encrypted, but encryption is not configured", row.orderID)
+	}
+
+	plaintext, err := r.keyring.Open(envelope.Sealed{
+		Ciphertext: row.ciphertext,
+		WrappedKey: row.dataKey,
+		KeyID:      deref(row.keyID),
+	}, row.orderID[:])
+	if err != nil {
+		return fmt.Errorf("decrypt delivery of order %s: %w", row.orderID, err)
+	}
+
+	var pii deliveryPII
+	if err = json.Unmarshal(plaintext, &pii); err != nil {
+		return fmt.Errorf("unmarshal delivery pii: %w", err)
+	}
+	delivery.Name, delivery.Phone, delivery.Address, delivery.Email = pii.Name, pii.Phone, pii.Address, pii.Email
+
+	return nil
+}
+
+// FindOrderIDsByEmail ищет заказы по точному email: по blind index у зашифрованных строк
+// и по открытой колонке у старых.
+func (r *OrderPostgresRepository) FindOrderIDsByEmail(ctx context.Context, email string) ([]uuid.UUID, error) {
+	const q = `
+		-- name: find_orders_by_email
+		SELECT order_id FROM orders.delivery
+		WHERE email_bidx = $1 OR lower(email) = $2
+	`
+	email = normalizeEmail(email)
+	return r.findOrderIDs(ctx, q, r.blindIndex(email), email)
+}
+
+// FindOrderIDsByPhone ищет заказы по телефону; сравниваются только цифры.
+func (r *OrderPostgresRepository) FindOrderIDsByPhone(ctx context.Context, phone string) ([]uuid.UUID, error) {
+	const q = `
+		-- name: find_orders_by_phone
+		SELECT order_id FROM orders.delivery
+		WHERE phone_bidx = $1 OR regexp_replace(phone, '\D', '', 'g') = $2
+	`
+	phone = normalizePhone(phone)
+	return r.findOrderIDs(ctx, q, r.blindIndex(phone), phone)
+}
+
+func (r *OrderPostgresRepository) findOrderIDs(ctx context.Context, q string, args ...any) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query delivery: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan order id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery: %w", mapError(err))
+	}
+
+	return ids, nil
+}
+
+// RotateDeliveryKeys переводит строки доставки на активный мастер-ключ: у зашифрованных
+// переворачивает ключ данных, открытые шифрует. Возвращает число обновлённых строк;
+// строки, которые не удалось обработать, пропускаются и попадают в ошибку.
+func (r *OrderPostgresRepository) RotateDeliveryKeys(ctx context.Context, batchSize int) (int, error) {
+	if r.keyring == nil {
+		return 0, nil
+	}
+
+	const qBatch = `
+		-- name: select_deliveries_to_rotate
+		SELECT id, order_id, name, phone, address, email, pii_ciphertext, pii_data_key, pii_key_id
+		FROM orders.delivery
+		WHERE pii_key_id IS DISTINCT FROM $1 AND id > $2 AND anonymized_at IS NULL
+		ORDER BY id
+		LIMIT $3
+	`
+	var (
+		rotated int
+		failed  []error
+		lastID  int64
+	)
+	for {
+		rows, err := r.db.Query(ctx, qBatch, r.keyring.ActiveKeyID(), lastID, batchSize)
+		if err != nil {
+			return rotated, fmt.Errorf("query delivery batch: %w", mapError(err))
+		}
+
+		var batch []deliveryRow
+		for rows.Next() {
+			var row deliveryRow
+			if err = rows.Scan(&row.id, &row.orderID, &row.name, &row.phone, &row.address, &row.email,
+				&row.ciphertext, &row.dataKey, &row.keyID); err != nil {
+				rows.Close()
+				return rotated, fmt.Errorf("scan delivery: %w", err)
+			}
+			batch = append(batch, row)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return rotated, fmt.Errorf("iterate delivery batch: %w", mapError(err))
+		}
+
+		for _, row := range batch {
+			lastID = row.id
+			if err = r.rotateDelivery(ctx, row); err != nil {
+				failed = append(failed, fmt.Errorf("delivery %d: %w", row.id, err))
+				continue
+			}
+			rotated++
+		}
+
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		return rotated, fmt.Errorf("%d delivery rows not rotated: %w", len(failed), errors.Join(failed...))
+	}
+	return rotated, nil
+}
+
+func (r *OrderPostgresRepository) rotateDelivery(ctx context.Context, row deliveryRow) error {
+	if row.ciphertext != nil {
+		sealed, err := r.keyring.Rewrap(envelope.Sealed{Ciphertext: row.ciphertext, WrappedKey: row.dataKey, KeyID: deref(row.keyID)})
+		if err != nil {
+			return err
+		}
+
+		const qRewrap = `
+			-- name: rewrap_delivery_key
+			UPDATE orders.delivery SET pii_data_key = $2, pii_key_id = $3
+			WHERE id = $1 AND pii_key_id IS NOT DISTINCT FROM $4
+		`
+		if _, err = r.db.Exec(ctx, qRewrap, row.id, sealed.WrappedKey, sealed.KeyID, row.keyID); err != nil {
+			return mapError(err)
+		}
+		return nil
+	}
+
+	var delivery domain.Delivery
+	if err := r.openDelivery(row, &delivery); err != nil {
+		return err
+	}
+	args, err := r.deliveryPIIArgs(row.orderID, delivery)
+	if err != nil {
+		return err
+	}
+
+	const qEncrypt = `
+		-- name: encrypt_delivery
+		UPDATE orders.delivery
+		SET name = $2, phone = $3, address = $4, email = $5,
+		    pii_ciphertext = $6, pii_data_key = $7, pii_key_id = $8, email_bidx = $9, phone_bidx = $10
+		WHERE id = $1 AND pii_ciphertext IS NULL
+	`
+	if _, err = r.db.Exec(ctx, qEncrypt, append([]any{row.id}, args...)...); err != nil {
+		return mapError(err)
+	}
+	return nil
+}
+
+func (r *OrderPostgresRepository) blindIndex(value string) []byte {
+	if r.keyring == nil {
+		return nil
+	}
+	return r.keyring.BlindIndex(value)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func normalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
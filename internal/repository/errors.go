@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"web_demoservice/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/puddle/v2"
+)
+
+const pgUniqueViolation = "23505"
+
+// mapError добавляет к ошибке pgx доменную причину, сохраняя исходную цепочку.
+func mapError(err error) error {
+	var (
+		pgErr   *pgconn.PgError
+		connErr *pgconn.ConnectError
+		netErr  net.Error
+	)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, err)
+	case errors.As(err, &pgErr):
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+		case unavailableCode(pgErr.Code):
+			return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+		}
+		return err
+	// Отмена запроса клиентом — не сбой БД, хотя pgx и считает такой запрос безопасным для повтора.
+	case errors.Is(err, context.Canceled):
+		return err
+	case errors.As(err, &connErr), pgconn.Timeout(err), pgconn.SafeToRetry(err),
+		errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF), errors.Is(err, net.ErrClosed),
+		errors.As(err, &netErr), errors.Is(err, puddle.ErrClosedPool):
+		return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+	}
+	return err
+}
+
+// unavailableCode: сервер не принимает запросы — соединение потеряно, идёт остановка или
+// исчерпаны подключения (классы 08, 57P0x и 53300).
+func unavailableCode(code string) bool {
+	switch {
+	case strings.HasPrefix(code, "08"):
+		return true
+	case code == "57P01", code == "57P02", code == "57P03", code == "53300":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"web_demoservice/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/puddle/v2"
+)
+
+func TestMapError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no rows", pgx.ErrNoRows, domain.ErrNotFound},
+		{"unique violation", &pgconn.PgError{Code: pgUniqueViolation}, domain.ErrConflict},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, domain.ErrUnavailable},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, domain.ErrUnavailable},
+		{"unexpected eof", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), domain.ErrUnavailable},
+		{"network", &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, domain.ErrUnavailable},
+		{"closed pool", puddle.ErrClosedPool, domain.ErrUnavailable},
+	}
+	for _, tc := range cases {
+		if err := mapError(tc.err); !errors.Is(err, tc.want) || !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v wrapping the original, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	for _, err := range []error{context.Canceled, &pgconn.PgError{Code: "23503"}, errors.New("scan failed")} {
+		if errors.Is(mapError(err), domain.ErrUnavailable) {
+			t.Errorf("%v must not be treated as unavailable", err)
+		}
+	}
+}
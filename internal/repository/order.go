@@ -4,27 +4,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 	"web_demoservice/internal/domain"
+	"web_demoservice/internal/infra/envelope"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewOrderPostgresRepository(db *pgxpool.Pool) *OrderPostgresRepository {
+// NewOrderPostgresRepository: keyring равен nil, если шифрование PII доставки выключено.
+func NewOrderPostgresRepository(db *pgxpool.Pool, keyring *envelope.Keyring) *OrderPostgresRepository {
 	return &OrderPostgresRepository{
-		db: db,
+		db:      db,
+		keyring: keyring,
 	}
 }
 
 type OrderPostgresRepository struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	keyring *envelope.Keyring
 }
 
-func (r *OrderPostgresRepository) Create(ctx context.Context, order domain.OrderWithInformation) (err error) {
+// Create сохраняет заказ и сообщает, был ли он вставлен: повторная доставка того же
+// заказа ничего не меняет и возвращает false.
+func (r *OrderPostgresRepository) Create(ctx context.Context, order domain.OrderWithInformation) (created bool, err error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin tx: %w", err)
+		return false, fmt.Errorf("failed to begin tx: %w", mapError(err))
 	}
 
 	defer func() {
@@ -32,60 +39,75 @@ func (r *OrderPostgresRepository) Create(ctx context.Context, order domain.Order
 			_ = tx.Rollback(ctx)
 			panic(p)
 		} else if err != nil {
-			_ = tx.Rollback(ctx)
-		} else {
-			err = tx.Commit(ctx)
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				err = errors.Join(err, fmt.Errorf("rollback tx: %w", mapError(rbErr)))
+			}
+			created = false
+		} else if err = tx.Commit(ctx); err != nil {
+			// Обрыв соединения на коммите — та же недоступность БД: консьюмер встанет на паузу.
+			err = fmt.Errorf("commit tx: %w", mapError(err))
+			created = false
 		}
 	}()
 
 	// 1. Вставка основного заказа
 	const qCreateOrder = `
+		-- name: insert_order
 		INSERT INTO orders.orders 
 		    (order_id, track_number, entry, locale, internal_signature, customer_id, 
 		     delivery_service, shardkey, sm_id, date_created, oof_shard) 
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (order_id) DO NOTHING;
 	`
-	_, err = tx.Exec(ctx, qCreateOrder,
+	tag, err := tx.Exec(ctx, qCreateOrder,
 		order.ID, order.TrackNumber, order.Entry, order.Locale,
 		order.InternalSignature, order.CustomerID, order.DeliveryService,
 		order.ShardKey, order.SmID, order.DateCreated, order.OofShard,
 	)
 	if err != nil {
-		return fmt.Errorf("insert order: %w", err)
+		return false, fmt.Errorf("insert order: %w", mapError(err))
 	}
+	created = tag.RowsAffected() > 0
 
-	// 2. Вставка данных о доставке
+	// 2. Вставка данных о доставке (PII шифруются, если задан keyring)
 	const qCreateDelivery = `
+		-- name: insert_delivery
 		INSERT INTO orders.delivery 
-		    (order_id, name, phone, zip, city, address, region, email) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		    (order_id, zip, city, region, name, phone, address, email,
+		     pii_ciphertext, pii_data_key, pii_key_id, email_bidx, phone_bidx) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		ON CONFLICT (order_id) DO NOTHING;
 	`
-	_, err = tx.Exec(ctx, qCreateDelivery,
-		order.ID, order.Delivery.Name, order.Delivery.Phone, order.Delivery.Zip,
-		order.Delivery.City, order.Delivery.Address, order.Delivery.Region, order.Delivery.Email,
-	)
+	piiArgs, err := r.deliveryPIIArgs(order.ID, order.Delivery)
 	if err != nil {
-		return fmt.Errorf("insert delivery: %w", err)
+		return false, err
+	}
+	_, err = tx.Exec(ctx, qCreateDelivery, append([]any{
+		order.ID, order.Delivery.Zip, order.Delivery.City, order.Delivery.Region,
+	}, piiArgs...)...)
+	if err != nil {
+		return false, fmt.Errorf("insert delivery: %w", mapError(err))
 	}
 
 	// 3. Обработка банка (Получаем ID по имени или создаем новый)
+	const qGetBank = "-- name: get_bank_id\nSELECT id FROM banks.banks WHERE name = $1"
+	const qCreateBank = "-- name: insert_bank\nINSERT INTO banks.banks (name) VALUES ($1) RETURNING id"
 	var bankID int64
-	err = tx.QueryRow(ctx, "SELECT id FROM banks.banks WHERE name = $1", order.Payment.Bank.Name).Scan(&bankID)
+	err = tx.QueryRow(ctx, qGetBank, order.Payment.Bank.Name).Scan(&bankID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			err = tx.QueryRow(ctx, "INSERT INTO banks.banks (name) VALUES ($1) RETURNING id", order.Payment.Bank.Name).Scan(&bankID)
+			err = tx.QueryRow(ctx, qCreateBank, order.Payment.Bank.Name).Scan(&bankID)
 			if err != nil {
-				return fmt.Errorf("insert bank: %w", err)
+				return false, fmt.Errorf("insert bank: %w", mapError(err))
 			}
 		} else {
-			return fmt.Errorf("query bank: %w", err)
+			return false, fmt.Errorf("query bank: %w", mapError(err))
 		}
 	}
 
 	// 4. Вставка платежа
 	const qCreatePayment = `
+		-- name: insert_payment
 		INSERT INTO orders.payments 
 		    (order_id, transaction, request_id, currency, provider, amount, 
 		     payment_dt, bank_id, delivery_cost, goods_total, custom_fee) 
@@ -98,11 +120,12 @@ func (r *OrderPostgresRepository) Create(ctx context.Context, order domain.Order
 		order.Payment.DeliveryCost, order.Payment.GoodsTotal, order.Payment.CustomFee,
 	)
 	if err != nil {
-		return fmt.Errorf("insert payment: %w", err)
+		return false, fmt.Errorf("insert payment: %w", mapError(err))
 	}
 
 	// 5. Вставка товаров и связей
 	const qCreateItem = `
+		-- name: insert_item
 		INSERT INTO orders.items 
 		    (chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status) 
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
@@ -110,6 +133,7 @@ func (r *OrderPostgresRepository) Create(ctx context.Context, order domain.Order
 		RETURNING id;
 	`
 	const qCreateOrderItem = `
+		-- name: insert_order_item
 		INSERT INTO orders.order_items (order_id, item_id) 
 		VALUES ($1, $2)
 		ON CONFLICT (order_id, item_id) DO NOTHING;
@@ -122,21 +146,27 @@ func (r *OrderPostgresRepository) Create(ctx context.Context, order domain.Order
 			item.Sale, item.Size, item.TotalPrice, item.NmID, item.Brand, item.Status,
 		).Scan(&itemID)
 		if err != nil {
-			return fmt.Errorf("insert item %s: %w", item.RID, err)
+			return false, fmt.Errorf("insert item %s: %w", item.RID, mapError(err))
 		}
 
 		_, err = tx.Exec(ctx, qCreateOrderItem, order.ID, itemID)
 		if err != nil {
-			return fmt.Errorf("link item to order: %w", err)
+			return false, fmt.Errorf("link item to order: %w", mapError(err))
 		}
 	}
 
-	return nil
+	return created, nil
 }
 
 func (r *OrderPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+	return r.GetPartsByID(ctx, id, domain.IncludeAll)
+}
+
+// GetPartsByID загружает заказ и только перечисленные в include вложенные сущности.
+func (r *OrderPostgresRepository) GetPartsByID(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
 	// 1. Получаем основные данные заказа
 	const qGetOrder = `
+		-- name: get_order
 		SELECT order_id, track_number, entry, locale, internal_signature, customer_id, 
 		       delivery_service, shardkey, sm_id, date_created, oof_shard 
 		FROM orders.orders 
@@ -150,27 +180,62 @@ func (r *OrderPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("order not found: %w", err)
+			return nil, fmt.Errorf("order not found: %w", mapError(err))
 		}
-		return nil, fmt.Errorf("query order: %w", err)
+		return nil, fmt.Errorf("query order: %w", mapError(err))
 	}
 
 	// 2. Получаем данные о доставке
+	if include.Has(domain.IncludeDelivery) {
+		if err = r.getDelivery(ctx, id, &ord.Delivery); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Получаем данные о платеже и банке
+	if include.Has(domain.IncludePayment) {
+		if err = r.getPayment(ctx, id, &ord.Payment); err != nil {
+			return nil, err
+		}
+	}
+
+	// 4. Получаем список товаров через связующую таблицу order_items
+	if include.Has(domain.IncludeItems) {
+		if ord.Items, err = r.getItems(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ord, nil
+}
+
+func (r *OrderPostgresRepository) getDelivery(ctx context.Context, id uuid.UUID, delivery *domain.Delivery) error {
 	const qGetDelivery = `
-		SELECT name, phone, zip, city, address, region, email 
+		-- name: get_delivery
+		SELECT id, zip, city, region, name, phone, address, email,
+		       pii_ciphertext, pii_data_key, pii_key_id
 		FROM orders.delivery 
 		WHERE order_id = $1
 	`
-	err = r.db.QueryRow(ctx, qGetDelivery, id).Scan(
-		&ord.Delivery.Name, &ord.Delivery.Phone, &ord.Delivery.Zip,
-		&ord.Delivery.City, &ord.Delivery.Address, &ord.Delivery.Region, &ord.Delivery.Email,
+	row := deliveryRow{orderID: id}
+	err := r.db.QueryRow(ctx, qGetDelivery, id).Scan(
+		&row.id, &delivery.Zip, &delivery.City, &delivery.Region,
+		&row.name, &row.phone, &row.address, &row.email,
+		&row.ciphertext, &row.dataKey, &row.keyID,
 	)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("query delivery: %w", err)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("query delivery: %w", mapError(err))
 	}
 
-	// 3. Получаем данные о платеже и банке
+	return r.openDelivery(row, delivery)
+}
+
+func (r *OrderPostgresRepository) getPayment(ctx context.Context, id uuid.UUID, payment *domain.PaymentWithBank) error {
 	const qGetPayment = `
+		-- name: get_payment
 		SELECT p.transaction, p.request_id, p.currency, p.provider, p.amount, 
 		       p.payment_dt, p.delivery_cost, p.goods_total, p.custom_fee, 
 		       b.id, b.name 
@@ -178,18 +243,22 @@ func (r *OrderPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 		JOIN banks.banks b ON p.bank_id = b.id
 		WHERE p.order_id = $1
 	`
-	err = r.db.QueryRow(ctx, qGetPayment, id).Scan(
-		&ord.Payment.Transaction, &ord.Payment.RequestID, &ord.Payment.Currency,
-		&ord.Payment.Provider, &ord.Payment.Amount, &ord.Payment.PaymentDt,
-		&ord.Payment.DeliveryCost, &ord.Payment.GoodsTotal, &ord.Payment.CustomFee,
-		&ord.Payment.Bank.ID, &ord.Payment.Bank.Name,
+	err := r.db.QueryRow(ctx, qGetPayment, id).Scan(
+		&payment.Transaction, &payment.RequestID, &payment.Currency,
+		&payment.Provider, &payment.Amount, &payment.PaymentDt,
+		&payment.DeliveryCost, &payment.GoodsTotal, &payment.CustomFee,
+		&payment.Bank.ID, &payment.Bank.Name,
 	)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("query payment: %w", err)
+		return fmt.Errorf("query payment: %w", mapError(err))
 	}
 
-	// 4. Получаем список товаров через связующую таблицу order_items
+	return nil
+}
+
+func (r *OrderPostgresRepository) getItems(ctx context.Context, id uuid.UUID) ([]domain.Item, error) {
 	const qGetItems = `
+		-- name: get_items
 		SELECT i.chrt_id, i.track_number, i.price, i.rid, i.name, 
 		       i.sale, i.size, i.total_price, i.nm_id, i.brand, i.status
 		FROM orders.items i
@@ -198,10 +267,11 @@ func (r *OrderPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 	`
 	rows, err := r.db.Query(ctx, qGetItems, id)
 	if err != nil {
-		return nil, fmt.Errorf("query items: %w", err)
+		return nil, fmt.Errorf("query items: %w", mapError(err))
 	}
 	defer rows.Close()
 
+	var items []domain.Item
 	for rows.Next() {
 		var item domain.Item
 		err := rows.Scan(
@@ -211,22 +281,23 @@ func (r *OrderPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 		if err != nil {
 			return nil, fmt.Errorf("scan item: %w", err)
 		}
-		ord.Items = append(ord.Items, item)
+		items = append(items, item)
 	}
 
-	return &ord, nil
+	return items, nil
 }
 
 func (r *OrderPostgresRepository) GetAllLast24Hours(ctx context.Context) ([]domain.OrderWithInformation, error) {
 	// 1. Получаем список ID заказов за последние 24 часа
 	const qGetIDs = `
+		-- name: get_recent_order_ids
 		SELECT order_id 
 		FROM orders.orders 
 		WHERE date_created >= NOW() - INTERVAL '24 hours'
 	`
 	rows, err := r.db.Query(ctx, qGetIDs)
 	if err != nil {
-		return nil, fmt.Errorf("query order ids: %w", err)
+		return nil, fmt.Errorf("query order ids: %w", mapError(err))
 	}
 	defer rows.Close()
 
@@ -253,9 +324,182 @@ func (r *OrderPostgresRepository) GetAllLast24Hours(ctx context.Context) ([]doma
 	return orders, nil
 }
 
+func (r *OrderPostgresRepository) List(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+	// 1. Получаем страницу заказов (keyset по date_created, order_id)
+	const qListOrders = `
+		-- name: list_orders
+		SELECT order_id, track_number, entry, locale, internal_signature, customer_id,
+		       delivery_service, shardkey, sm_id, date_created, oof_shard
+		FROM orders.orders
+		WHERE $1::timestamptz IS NULL OR (date_created, order_id) < ($1, $2)
+		ORDER BY date_created DESC, order_id DESC
+		LIMIT $3
+	`
+	var (
+		afterTime *time.Time
+		afterID   *uuid.UUID
+	)
+	if after != nil {
+		afterTime, afterID = &after.DateCreated, &after.ID
+	}
+
+	rows, err := r.db.Query(ctx, qListOrders, afterTime, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query orders: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	var orders []domain.OrderWithInformation
+	for rows.Next() {
+		var ord domain.OrderWithInformation
+		if err := rows.Scan(
+			&ord.ID, &ord.TrackNumber, &ord.Entry, &ord.Locale,
+			&ord.InternalSignature, &ord.CustomerID, &ord.DeliveryService,
+			&ord.ShardKey, &ord.SmID, &ord.DateCreated, &ord.OofShard,
+		); err != nil {
+			return nil, fmt.Errorf("scan order: %w", err)
+		}
+		orders = append(orders, ord)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate orders: %w", mapError(err))
+	}
+	if len(orders) == 0 {
+		return []domain.OrderWithInformation{}, nil
+	}
+
+	// 2. Доставка, платежи и товары страницы — по одному запросу на сущность
+	ids := make([]uuid.UUID, len(orders))
+	byID := make(map[uuid.UUID]*domain.OrderWithInformation, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].ID
+		byID[orders[i].ID] = &orders[i]
+	}
+	if err = r.loadDeliveries(ctx, ids, byID); err != nil {
+		return nil, err
+	}
+	if err = r.loadPayments(ctx, ids, byID); err != nil {
+		return nil, err
+	}
+	if err = r.loadItems(ctx, ids, byID); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *OrderPostgresRepository) loadDeliveries(ctx context.Context, ids []uuid.UUID, byID map[uuid.UUID]*domain.OrderWithInformation) error {
+	const qListDeliveries = `
+		-- name: list_deliveries
+		SELECT order_id, id, zip, city, region, name, phone, address, email,
+		       pii_ciphertext, pii_data_key, pii_key_id
+		FROM orders.delivery
+		WHERE order_id = ANY($1)
+	`
+	rows, err := r.db.Query(ctx, qListDeliveries, ids)
+	if err != nil {
+		return fmt.Errorf("query deliveries: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row      deliveryRow
+			delivery domain.Delivery
+		)
+		if err := rows.Scan(
+			&row.orderID, &row.id, &delivery.Zip, &delivery.City, &delivery.Region,
+			&row.name, &row.phone, &row.address, &row.email,
+			&row.ciphertext, &row.dataKey, &row.keyID,
+		); err != nil {
+			return fmt.Errorf("scan delivery: %w", err)
+		}
+		if err := r.openDelivery(row, &delivery); err != nil {
+			return err
+		}
+		byID[row.orderID].Delivery = delivery
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate deliveries: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *OrderPostgresRepository) loadPayments(ctx context.Context, ids []uuid.UUID, byID map[uuid.UUID]*domain.OrderWithInformation) error {
+	const qListPayments = `
+		-- name: list_payments
+		SELECT p.order_id, p.transaction, p.request_id, p.currency, p.provider, p.amount,
+		       p.payment_dt, p.delivery_cost, p.goods_total, p.custom_fee,
+		       b.id, b.name
+		FROM orders.payments p
+		JOIN banks.banks b ON p.bank_id = b.id
+		WHERE p.order_id = ANY($1)
+	`
+	rows, err := r.db.Query(ctx, qListPayments, ids)
+	if err != nil {
+		return fmt.Errorf("query payments: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderID uuid.UUID
+			payment domain.PaymentWithBank
+		)
+		if err := rows.Scan(
+			&orderID, &payment.Transaction, &payment.RequestID, &payment.Currency,
+			&payment.Provider, &payment.Amount, &payment.PaymentDt,
+			&payment.DeliveryCost, &payment.GoodsTotal, &payment.CustomFee,
+			&payment.Bank.ID, &payment.Bank.Name,
+		); err != nil {
+			return fmt.Errorf("scan payment: %w", err)
+		}
+		byID[orderID].Payment = payment
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate payments: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *OrderPostgresRepository) loadItems(ctx context.Context, ids []uuid.UUID, byID map[uuid.UUID]*domain.OrderWithInformation) error {
+	const qListItems = `
+		-- name: list_items
+		SELECT oi.order_id, i.chrt_id, i.track_number, i.price, i.rid, i.name,
+		       i.sale, i.size, i.total_price, i.nm_id, i.brand, i.status
+		FROM orders.items i
+		JOIN orders.order_items oi ON i.id = oi.item_id
+		WHERE oi.order_id = ANY($1)
+	`
+	rows, err := r.db.Query(ctx, qListItems, ids)
+	if err != nil {
+		return fmt.Errorf("query items: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderID uuid.UUID
+			item    domain.Item
+		)
+		if err := rows.Scan(
+			&orderID, &item.ChrtID, &item.TrackNumber, &item.Price, &item.RID, &item.Name,
+			&item.Sale, &item.Size, &item.TotalPrice, &item.NmID, &item.Brand, &item.Status,
+		); err != nil {
+			return fmt.Errorf("scan item: %w", err)
+		}
+		ord := byID[orderID]
+		ord.Items = append(ord.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate items: %w", mapError(err))
+	}
+	return nil
+}
+
 func (r *OrderPostgresRepository) Ping(ctx context.Context) error {
 	if err := r.db.Ping(ctx); err != nil {
-		return fmt.Errorf("ping db: %w", err)
+		return fmt.Errorf("ping db: %w", mapError(err))
 	}
 
 	return nil
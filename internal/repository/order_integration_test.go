@@ -6,15 +6,20 @@ package repository
 import (
 	"context"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 	"web_demoservice/internal/domain"
+	"web_demoservice/internal/infra/envelope"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func TestOrderPostgresRepository_CreateAndGet(t *testing.T) {
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
 	dsn := os.Getenv("TEST_DB_DSN")
 	if dsn == "" {
 		t.Skip("TEST_DB_DSN not set")
@@ -30,7 +35,7 @@ func TestOrderPostgresRepository_CreateAndGet(t *testing.T) {
 	if err != nil {
 		t.Fatalf("connect db: %v", err)
 	}
-	defer pool.Close()
+	t.Cleanup(pool.Close)
 
 	var table *string
 	if err := pool.QueryRow(ctx, "SELECT to_regclass('orders.orders')").Scan(&table); err != nil {
@@ -40,9 +45,11 @@ func TestOrderPostgresRepository_CreateAndGet(t *testing.T) {
 		t.Skip("schema not migrated")
 	}
 
-	repo := NewOrderPostgresRepository(pool)
-	order := sampleOrder(uuid.New())
+	return pool
+}
 
+func cleanupOrder(t *testing.T, pool *pgxpool.Pool, order domain.OrderWithInformation) {
+	ctx := context.Background()
 	t.Cleanup(func() {
 		_, _ = pool.Exec(ctx, "DELETE FROM orders.order_items WHERE order_id = $1", order.ID)
 		_, _ = pool.Exec(ctx, "DELETE FROM orders.payments WHERE order_id = $1", order.ID)
@@ -51,9 +58,21 @@ func TestOrderPostgresRepository_CreateAndGet(t *testing.T) {
 		_, _ = pool.Exec(ctx, "DELETE FROM orders.items WHERE rid = $1", order.Items[0].RID)
 		_, _ = pool.Exec(ctx, "DELETE FROM banks.banks WHERE name = $1", order.Payment.Bank.Name)
 	})
+}
 
-	if err := repo.Create(ctx, order); err != nil {
-		t.Fatalf("create order: %v", err)
+func TestOrderPostgresRepository_CreateAndGet(t *testing.T) {
+	pool := newTestPool(t)
+	ctx := context.Background()
+
+	repo := NewOrderPostgresRepository(pool, nil)
+	order := sampleOrder(uuid.New())
+	cleanupOrder(t, pool, order)
+
+	if created, err := repo.Create(ctx, order); err != nil || !created {
+		t.Fatalf("create order: %v, created=%v", err, created)
+	}
+	if created, err := repo.Create(ctx, order); err != nil || created {
+		t.Fatalf("redelivered order must not be created again: %v, created=%v", err, created)
 	}
 
 	got, err := repo.GetByID(ctx, order.ID)
@@ -93,6 +112,129 @@ func TestOrderPostgresRepository_CreateAndGet(t *testing.T) {
 	}
 }
 
+func TestOrderPostgresRepository_EncryptedDelivery(t *testing.T) {
+	pool := newTestPool(t)
+	ctx := context.Background()
+
+	oldKeyfile := testKeyfile(t, "k1", nil)
+	repo := NewOrderPostgresRepository(pool, mustKeyring(t, oldKeyfile))
+	order := sampleOrder(uuid.New())
+	order.Delivery.Email = "Enc-" + order.ID.String()[:8] + "@test.com"
+	cleanupOrder(t, pool, order)
+
+	if _, err := repo.Create(ctx, order); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	var plainEmail *string
+	var keyID string
+	err := pool.QueryRow(ctx, "SELECT email, pii_key_id FROM orders.delivery WHERE order_id = $1", order.ID).Scan(&plainEmail, &keyID)
+	if err != nil {
+		t.Fatalf("query raw delivery: %v", err)
+	}
+	if plainEmail != nil || keyID != "k1" {
+		t.Fatalf("expected encrypted row with key k1, got email %v key %s", plainEmail, keyID)
+	}
+
+	got, err := repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("get order: %v", err)
+	}
+	if got.Delivery.Email != order.Delivery.Email || got.Delivery.Phone != order.Delivery.Phone {
+		t.Fatalf("expected decrypted delivery, got %+v", got.Delivery)
+	}
+
+	ids, err := repo.FindOrderIDsByEmail(ctx, "  "+strings.ToUpper(order.Delivery.Email))
+	if err != nil || len(ids) != 1 || ids[0] != order.ID {
+		t.Fatalf("expected to find order by email, got %v, %v", ids, err)
+	}
+
+	// Ротация: новый активный ключ, старый остаётся для чтения до переворачивания.
+	rotated := NewOrderPostgresRepository(pool, mustKeyring(t, testKeyfile(t, "k2", &oldKeyfile)))
+	if _, err = rotated.RotateDeliveryKeys(ctx, 100); err != nil {
+		t.Fatalf("rotate keys: %v", err)
+	}
+	if err = pool.QueryRow(ctx, "SELECT pii_key_id FROM orders.delivery WHERE order_id = $1", order.ID).Scan(&keyID); err != nil {
+		t.Fatalf("query key id: %v", err)
+	}
+	if keyID != "k2" {
+		t.Fatalf("expected key k2 after rotation, got %s", keyID)
+	}
+	if got, err = rotated.GetByID(ctx, order.ID); err != nil || got.Delivery.Email != order.Delivery.Email {
+		t.Fatalf("expected decrypted delivery after rotation, got %+v, %v", got, err)
+	}
+}
+
+// testKeyfile создаёт ключ id и делает его активным; ключи prev сохраняются.
+func testKeyfile(t *testing.T, id string, prev *envelope.Keyfile) envelope.Keyfile {
+	t.Helper()
+
+	kf := envelope.Keyfile{ActiveKeyID: id, MasterKeys: map[string]string{}}
+	if prev != nil {
+		for k, v := range prev.MasterKeys {
+			kf.MasterKeys[k] = v
+		}
+		kf.BlindIndexKey = prev.BlindIndexKey
+	} else {
+		key, err := envelope.GenerateKey()
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		kf.BlindIndexKey = key
+	}
+
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	kf.MasterKeys[id] = key
+	return kf
+}
+
+func mustKeyring(t *testing.T, kf envelope.Keyfile) *envelope.Keyring {
+	t.Helper()
+
+	k, err := envelope.NewKeyring(kf)
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	return k
+}
+
+func TestOrderPostgresRepository_ListMatchesGetByID(t *testing.T) {
+	pool := newTestPool(t)
+	ctx := context.Background()
+
+	repo := NewOrderPostgresRepository(pool, nil)
+	older, newer := sampleOrder(uuid.New()), sampleOrder(uuid.New())
+	older.DateCreated = newer.DateCreated.Add(-time.Second)
+	for _, order := range []domain.OrderWithInformation{older, newer} {
+		cleanupOrder(t, pool, order)
+		if _, err := repo.Create(ctx, order); err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+
+	// Курсор сразу после newer: страница начинается с двух созданных заказов.
+	cursor := &domain.OrderCursor{DateCreated: newer.DateCreated.Add(time.Second), ID: uuid.Max}
+	page, err := repo.List(ctx, cursor, 2)
+	if err != nil {
+		t.Fatalf("list orders: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != newer.ID || page[1].ID != older.ID {
+		t.Fatalf("unexpected page order: %+v", page)
+	}
+	for _, got := range page {
+		want, err := repo.GetByID(ctx, got.ID)
+		if err != nil {
+			t.Fatalf("get order: %v", err)
+		}
+		if !reflect.DeepEqual(got, *want) {
+			t.Fatalf("list and get differ for %s:\n%+v\n%+v", got.ID, got, *want)
+		}
+	}
+}
+
 func sampleOrder(id uuid.UUID) domain.OrderWithInformation {
 	internalSignature := "sig"
 	deliveryService := "delivery"
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy — экспоненциальная пауза между попытками: Initial, затем вдвое больше, но не
+// больше Max (по умолчанию минута). Attempts = 0 — повторять, пока не отменён ctx.
+type Policy struct {
+	Attempts int
+	Initial  time.Duration
+	Max      time.Duration
+}
+
+// Delay возвращает паузу после попытки с номером attempt (с нуля). К паузе добавляется
+// до 20% случайного разброса, чтобы реплики не ломились в зависимость одновременно.
+func (p Policy) Delay(attempt int) time.Duration {
+	d, limit := p.Initial, p.Max
+	if d <= 0 {
+		d = 100 * time.Millisecond
+	}
+	if limit <= 0 {
+		limit = time.Minute
+	}
+	for i := 0; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	d = min(d, limit)
+	return d + rand.N(d/5+1)
+}
+
+// Do вызывает fn, пока она не вернёт nil, не кончатся попытки или не отменится ctx.
+// Каждая неудача логируется с именем зависимости; возвращается последняя ошибка.
+func Do(ctx context.Context, p Policy, name string, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if attempt > 0 {
+				slog.InfoContext(ctx, "dependency is ready", slog.String("dependency", name), slog.Int("attempts", attempt+1))
+			}
+			return nil
+		}
+		if p.Attempts > 0 && attempt+1 >= p.Attempts {
+			return fmt.Errorf("%s: giving up after %d attempts: %w", name, attempt+1, err)
+		}
+
+		delay := p.Delay(attempt)
+		slog.WarnContext(ctx, "dependency is not ready, retrying",
+			slog.String("dependency", name),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("backoff", delay),
+			slog.Any("error", err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: %w (last error: %w)", name, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
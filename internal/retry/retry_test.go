@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPolicy_DelayGrowsUpToMax(t *testing.T) {
+	p := Policy{Initial: 100 * time.Millisecond, Max: time.Second}
+
+	cases := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{50, time.Second},
+	}
+	for _, tc := range cases {
+		got := p.Delay(tc.attempt)
+		if got < tc.base || got > tc.base+tc.base/5 {
+			t.Fatalf("attempt %d: expected %s..+20%%, got %s", tc.attempt, tc.base, got)
+		}
+	}
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), Policy{Attempts: 5, Initial: time.Millisecond}, "postgres", func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestDo_GivesUpAfterAttempts(t *testing.T) {
+	cause := errors.New("connection refused")
+	calls := 0
+	err := Do(context.Background(), Policy{Attempts: 2, Initial: time.Millisecond}, "kafka", func(context.Context) error {
+		calls++
+		return cause
+	})
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected last error, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestDo_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Do(ctx, Policy{Initial: time.Hour}, "postgres", func(context.Context) error {
+		cancel()
+		return errors.New("connection refused")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"web_demoservice/internal/domain"
+)
+
+const (
+	DefaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditRepository interface {
+	Query(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error)
+}
+
+func NewAuditService(repo AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// AuditService отвечает на запросы к журналу аудита; запись идёт через audit.Writer.
+type AuditService struct {
+	repo AuditRepository
+}
+
+// QueryEvents требует хотя бы один из фильтров order_id / actor: полный журнал не отдаётся.
+func (s *AuditService) QueryEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	var fields []FieldError
+	if filter.OrderID == nil && filter.Actor == "" {
+		fields = append(fields, FieldError{Field: "order_id", Reason: "order_id or actor is required"})
+	}
+	if filter.Before < 0 {
+		fields = append(fields, FieldError{Field: "before", Reason: "must be positive"})
+	}
+	switch {
+	case filter.Limit < 0 || filter.Limit > maxAuditLimit:
+		fields = append(fields, FieldError{Field: "limit", Reason: "must be between 1 and 1000"})
+	case filter.Limit == 0:
+		filter.Limit = DefaultAuditLimit
+	}
+	if len(fields) > 0 {
+		return nil, InvalidInput(CodeInvalidAuditFilter, "invalid audit filter", fields...)
+	}
+
+	events, err := s.repo.Query(ctx, filter)
+	if err != nil {
+		return nil, fromRepository("query audit events", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"web_demoservice/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type mockAuditRepo struct {
+	filter domain.AuditFilter
+	err    error
+}
+
+func (m *mockAuditRepo) Query(_ context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	m.filter = filter
+	return nil, m.err
+}
+
+func TestAuditService_QueryEvents_RequiresFilter(t *testing.T) {
+	svc := NewAuditService(&mockAuditRepo{})
+
+	_, err := svc.QueryEvents(context.Background(), domain.AuditFilter{})
+	if KindOf(err) != KindInvalidInput || AsError(err).Code != CodeInvalidAuditFilter {
+		t.Fatalf("expected invalid audit filter, got %v", err)
+	}
+}
+
+func TestAuditService_QueryEvents_DefaultLimit(t *testing.T) {
+	repo := &mockAuditRepo{}
+	svc := NewAuditService(repo)
+	id := uuid.New()
+
+	if _, err := svc.QueryEvents(context.Background(), domain.AuditFilter{OrderID: &id}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.filter.Limit != DefaultAuditLimit {
+		t.Fatalf("expected default limit %d, got %d", DefaultAuditLimit, repo.filter.Limit)
+	}
+}
+
+func TestAuditService_QueryEvents_UnavailableIsTyped(t *testing.T) {
+	svc := NewAuditService(&mockAuditRepo{err: errors.Join(domain.ErrUnavailable, errors.New("conn refused"))})
+
+	_, err := svc.QueryEvents(context.Background(), domain.AuditFilter{Actor: "frontend"})
+	if KindOf(err) != KindUnavailable {
+		t.Fatalf("expected unavailable, got %v", err)
+	}
+}
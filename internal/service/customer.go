@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"web_demoservice/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type CustomerRepository interface {
+	FindOrderIDsByCustomer(ctx context.Context, customerID string) ([]uuid.UUID, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	AnonymizeDeliveries(ctx context.Context, orderIDs []uuid.UUID) (int, error)
+}
+
+// CacheInvalidator убирает заказы из кэша на время анонимизации и не даёт вернуть их туда до release.
+type CacheInvalidator interface {
+	Hold(ctx context.Context, keys []uuid.UUID) (release func())
+}
+
+func NewCustomerService(repo CustomerRepository, cache CacheInvalidator) *CustomerService {
+	return &CustomerService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// CustomerService обслуживает запросы клиентов на доступ к данным и их удаление (GDPR).
+type CustomerService struct {
+	repo  CustomerRepository
+	cache CacheInvalidator
+}
+
+// ExportCustomer читает заказы клиента из БД, минуя кэш.
+func (s *CustomerService) ExportCustomer(ctx context.Context, customerID string) (*domain.CustomerExport, error) {
+	ids, err := s.customerOrderIDs(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.CustomerExport{
+		CustomerID: customerID,
+		ExportedAt: time.Now().UTC(),
+		Orders:     make([]domain.OrderWithInformation, 0, len(ids)),
+	}
+	for _, id := range ids {
+		order, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fromRepository(fmt.Sprintf("get order %s", id), err)
+		}
+		export.Orders = append(export.Orders, *order)
+	}
+
+	return export, nil
+}
+
+// EraseCustomer анонимизирует доставку всех заказов клиента и убирает их из кэша.
+// Повторный вызов безопасен: уже анонимизированные строки не меняются.
+func (s *CustomerService) EraseCustomer(ctx context.Context, customerID string) (*domain.CustomerErasure, error) {
+	ids, err := s.customerOrderIDs(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Параллельный GetOrder мог прочитать строку до коммита: пока идёт анонимизация,
+	// он не положит её обратно в кэш.
+	release := s.cache.Hold(ctx, ids)
+	defer release()
+
+	anonymized, err := s.repo.AnonymizeDeliveries(ctx, ids)
+	if err != nil {
+		return nil, fromRepository("anonymize deliveries", err)
+	}
+
+	return &domain.CustomerErasure{
+		CustomerID: customerID,
+		OrderIDs:   ids,
+		Anonymized: anonymized,
+	}, nil
+}
+
+func (s *CustomerService) customerOrderIDs(ctx context.Context, customerID string) ([]uuid.UUID, error) {
+	if strings.TrimSpace(customerID) == "" {
+		return nil, InvalidInput(CodeInvalidCustomerID, "customer_id is required",
+			FieldError{Field: "customer_id", Reason: "required"})
+	}
+
+	ids, err := s.repo.FindOrderIDsByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, fromRepository("find customer orders", err)
+	}
+	if len(ids) == 0 {
+		return nil, NotFound(CodeCustomerNotFound, "customer has no orders", nil)
+	}
+	return ids, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"web_demoservice/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type mockCustomerRepo struct {
+	ids        []uuid.UUID
+	anonymized []uuid.UUID
+}
+
+func (m *mockCustomerRepo) FindOrderIDsByCustomer(_ context.Context, _ string) ([]uuid.UUID, error) {
+	return m.ids, nil
+}
+
+func (m *mockCustomerRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+	order := domain.OrderWithInformation{}
+	order.ID = id
+	return &order, nil
+}
+
+func (m *mockCustomerRepo) AnonymizeDeliveries(_ context.Context, ids []uuid.UUID) (int, error) {
+	m.anonymized = append(m.anonymized, ids...)
+	return len(ids), nil
+}
+
+type mockInvalidator struct {
+	held     []uuid.UUID
+	released bool
+}
+
+func (m *mockInvalidator) Hold(_ context.Context, keys []uuid.UUID) func() {
+	m.held = append(m.held, keys...)
+	return func() { m.released = true }
+}
+
+func TestCustomerService_EraseCustomer_PurgesCache(t *testing.T) {
+	repo := &mockCustomerRepo{ids: []uuid.UUID{uuid.New(), uuid.New()}}
+	cache := &mockInvalidator{}
+	svc := NewCustomerService(repo, cache)
+
+	erasure, err := svc.EraseCustomer(context.Background(), "customer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if erasure.Anonymized != 2 || len(repo.anonymized) != 2 {
+		t.Fatalf("expected both deliveries to be anonymized, got %+v", erasure)
+	}
+	if len(cache.held) != 2 || cache.held[0] != repo.ids[0] || cache.held[1] != repo.ids[1] {
+		t.Fatalf("expected cache entries of both orders to be purged, got %v", cache.held)
+	}
+	if !cache.released {
+		t.Fatalf("expected cache hold to be released after erase")
+	}
+}
+
+func TestCustomerService_ExportCustomer(t *testing.T) {
+	repo := &mockCustomerRepo{ids: []uuid.UUID{uuid.New()}}
+	svc := NewCustomerService(repo, &mockInvalidator{})
+
+	export, err := svc.ExportCustomer(context.Background(), "customer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(export.Orders) != 1 || export.Orders[0].ID != repo.ids[0] || export.ExportedAt.IsZero() {
+		t.Fatalf("unexpected export: %+v", export)
+	}
+}
+
+func TestCustomerService_UnknownCustomer(t *testing.T) {
+	repo := &mockCustomerRepo{}
+	svc := NewCustomerService(repo, &mockInvalidator{})
+
+	_, err := svc.EraseCustomer(context.Background(), "nobody")
+	if KindOf(err) != KindNotFound || AsError(err).Code != CodeCustomerNotFound {
+		t.Fatalf("expected customer_not_found, got %v", err)
+	}
+	if repo.anonymized != nil {
+		t.Fatalf("nothing must be anonymized for unknown customer")
+	}
+
+	if _, err = svc.ExportCustomer(context.Background(), " "); KindOf(err) != KindInvalidInput {
+		t.Fatalf("expected invalid input for blank customer_id, got %v", err)
+	}
+}
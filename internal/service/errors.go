@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"web_demoservice/internal/domain"
+)
+
+type Kind string
+
+const (
+	KindInternal        Kind = "internal"
+	KindNotFound        Kind = "not_found"
+	KindInvalidInput    Kind = "invalid_input"
+	KindConflict        Kind = "conflict"
+	KindUnavailable     Kind = "unavailable"
+	KindUnauthenticated Kind = "unauthenticated"
+	KindForbidden       Kind = "forbidden"
+	KindRateLimited     Kind = "rate_limited"
+	KindTooLarge        Kind = "too_large"
+)
+
+// Стабильные коды ошибок: клиенты опираются на них, а не на текст сообщения.
+const (
+	CodeInternal              = "internal_error"
+	CodeOrderNotFound         = "order_not_found"
+	CodeInvalidOrderID        = "invalid_order_id"
+	CodeInvalidFieldSelection = "invalid_field_selection"
+	CodeOrderConflict         = "order_conflict"
+	CodeStorageUnavailable    = "storage_unavailable"
+	CodeUnauthenticated       = "unauthenticated"
+	CodeInsufficientScope     = "insufficient_scope"
+	CodeRateLimited           = "rate_limited"
+	CodeInvalidAuditFilter    = "invalid_audit_filter"
+	CodeCustomerNotFound      = "customer_not_found"
+	CodeInvalidCustomerID     = "invalid_customer_id"
+	CodeInvalidCacheQuery     = "invalid_cache_query"
+	CodeCacheEntryNotFound    = "cache_entry_not_found"
+	CodeRequestTooLarge       = "request_too_large"
+)
+
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Error — типизированная ошибка сервисного слоя. Транспорт отображает Kind в статус,
+// Code и Message отдаются клиенту как есть, Err остаётся только в логах.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(code, message string, err error) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message, Err: err}
+}
+
+func InvalidInput(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindInvalidInput, Code: code, Message: message, Fields: fields}
+}
+
+func Conflict(code, message string, err error) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message, Err: err}
+}
+
+func Unavailable(code, message string, err error) *Error {
+	return &Error{Kind: KindUnavailable, Code: code, Message: message, Err: err}
+}
+
+func Unauthenticated(code, message string, err error) *Error {
+	return &Error{Kind: KindUnauthenticated, Code: code, Message: message, Err: err}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func RateLimited(code, message string) *Error {
+	return &Error{Kind: KindRateLimited, Code: code, Message: message}
+}
+
+func TooLarge(code, message string) *Error {
+	return &Error{Kind: KindTooLarge, Code: code, Message: message}
+}
+
+// AsError достаёт *Error из цепочки; для прочих ошибок возвращает internal_error.
+func AsError(err error) *Error {
+	var svcErr *Error
+	if errors.As(err, &svcErr) {
+		return svcErr
+	}
+	return &Error{Kind: KindInternal, Code: CodeInternal, Message: "internal server error", Err: err}
+}
+
+func KindOf(err error) Kind {
+	if err == nil {
+		return ""
+	}
+	return AsError(err).Kind
+}
+
+// fromRepository переводит доменные ошибки хранилища в ошибки сервиса.
+func fromRepository(op string, err error) error {
+	wrapped := fmt.Errorf("%s: %w", op, err)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return NotFound(CodeOrderNotFound, "order not found", wrapped)
+	case errors.Is(err, domain.ErrConflict):
+		return Conflict(CodeOrderConflict, "order conflicts with existing data", wrapped)
+	case errors.Is(err, domain.ErrUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return Unavailable(CodeStorageUnavailable, "storage is temporarily unavailable", wrapped)
+	}
+	return wrapped
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"web_demoservice/internal/domain"
 
@@ -10,34 +9,43 @@ import (
 )
 
 type OrderRepository interface {
-	Create(ctx context.Context, order domain.OrderWithInformation) error
+	Create(ctx context.Context, order domain.OrderWithInformation) (bool, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	GetPartsByID(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error)
 	GetAllLast24Hours(ctx context.Context) ([]domain.OrderWithInformation, error)
+	List(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error)
 }
 
 type Cache interface {
 	Set(ctx context.Context, key uuid.UUID, value domain.OrderWithInformation)
 	Get(ctx context.Context, key uuid.UUID) (*domain.OrderWithInformation, bool)
+	Version(ctx context.Context, key uuid.UUID) (*domain.OrderVersion, bool)
 }
 
 func NewOrderService(repo OrderRepository, cache Cache) *OrderService {
 	return &OrderService{
-		repo:  repo,
-		cache: cache,
+		repo:     repo,
+		cache:    cache,
+		watchers: newWatchers(),
 	}
 }
 
 type OrderService struct {
-	cache Cache
-	repo  OrderRepository
+	cache    Cache
+	repo     OrderRepository
+	watchers *watchers
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, order domain.OrderWithInformation) error {
-	err := s.repo.Create(ctx, order)
+	created, err := s.repo.Create(ctx, order)
 	if err != nil {
-		return fmt.Errorf("create order: %w", err)
+		return fromRepository("create order", err)
 	}
 
+	// Повторно доставленный заказ подписчики уже получили.
+	if created {
+		s.watchers.publish(order)
+	}
 	return nil
 }
 
@@ -48,17 +56,56 @@ func (s *OrderService) GetOrder(ctx context.Context, id uuid.UUID) (*domain.Orde
 
 	order, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("get order: %w", err)
+		return nil, fromRepository("get order", err)
 	}
 
 	s.cache.Set(ctx, (*order).ID, *order)
 	return order, nil
 }
 
+// GetOrderParts отдаёт заказ, в котором гарантированно загружены части из include.
+// Из кэша приходит полный заказ; при промахе неполный заказ читается из БД без
+// лишних запросов и в кэш не попадает.
+func (s *OrderService) GetOrderParts(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
+	if include == domain.IncludeAll {
+		return s.GetOrder(ctx, id)
+	}
+
+	if ord, ok := s.cache.Get(ctx, id); ok {
+		return ord, nil
+	}
+
+	order, err := s.repo.GetPartsByID(ctx, id, include)
+	if err != nil {
+		return nil, fromRepository("get order parts", err)
+	}
+
+	return order, nil
+}
+
+// OrderVersion отдаёт валидаторы заказа только из кэша: промах не идёт в БД.
+func (s *OrderService) OrderVersion(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool) {
+	return s.cache.Version(ctx, id)
+}
+
+func (s *OrderService) ListOrders(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+	orders, err := s.repo.List(ctx, after, limit)
+	if err != nil {
+		return nil, fromRepository("list orders", err)
+	}
+
+	return orders, nil
+}
+
+// WatchOrders возвращает канал заказов, сохранённых после подписки. Канал закрывается при отмене ctx.
+func (s *OrderService) WatchOrders(ctx context.Context) <-chan domain.OrderWithInformation {
+	return s.watchers.subscribe(ctx)
+}
+
 func (s *OrderService) WarmUp(ctx context.Context) error {
 	orders, err := s.repo.GetAllLast24Hours(ctx)
 	if err != nil {
-		return fmt.Errorf("repo get all: %w", err)
+		return fromRepository("repo get all", err)
 	}
 
 	for _, ord := range orders {
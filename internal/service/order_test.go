@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 	"web_demoservice/internal/domain"
@@ -11,20 +12,22 @@ import (
 )
 
 type mockOrderRepo struct {
-	createFn          func(ctx context.Context, order domain.OrderWithInformation) error
+	createFn          func(ctx context.Context, order domain.OrderWithInformation) (bool, error)
 	getByIDFn         func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
 	getAllLast24Hours func(ctx context.Context) ([]domain.OrderWithInformation, error)
+	listFn            func(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error)
+	getPartsByIDFn    func(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error)
 	createCalls       int
 	getByIDCalls      int
 	getAllLast24Calls int
 }
 
-func (m *mockOrderRepo) Create(ctx context.Context, order domain.OrderWithInformation) error {
+func (m *mockOrderRepo) Create(ctx context.Context, order domain.OrderWithInformation) (bool, error) {
 	m.createCalls++
 	if m.createFn != nil {
 		return m.createFn(ctx, order)
 	}
-	return nil
+	return true, nil
 }
 
 func (m *mockOrderRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
@@ -35,6 +38,13 @@ func (m *mockOrderRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Orde
 	return nil, nil
 }
 
+func (m *mockOrderRepo) GetPartsByID(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
+	if m.getPartsByIDFn != nil {
+		return m.getPartsByIDFn(ctx, id, include)
+	}
+	return nil, nil
+}
+
 func (m *mockOrderRepo) GetAllLast24Hours(ctx context.Context) ([]domain.OrderWithInformation, error) {
 	m.getAllLast24Calls++
 	if m.getAllLast24Hours != nil {
@@ -43,6 +53,13 @@ func (m *mockOrderRepo) GetAllLast24Hours(ctx context.Context) ([]domain.OrderWi
 	return nil, nil
 }
 
+func (m *mockOrderRepo) List(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, after, limit)
+	}
+	return nil, nil
+}
+
 type mockCache struct {
 	getFn    func(ctx context.Context, key uuid.UUID) (*domain.OrderWithInformation, bool)
 	setFn    func(ctx context.Context, key uuid.UUID, value domain.OrderWithInformation)
@@ -65,13 +82,17 @@ func (m *mockCache) Get(ctx context.Context, key uuid.UUID) (*domain.OrderWithIn
 	return nil, false
 }
 
+func (m *mockCache) Version(ctx context.Context, key uuid.UUID) (*domain.OrderVersion, bool) {
+	return nil, false
+}
+
 var _ Cache = (*mockCache)(nil)
 
 func TestOrderService_CreateOrder_PropagatesError(t *testing.T) {
 	wantErr := errors.New("repo error")
 	repo := &mockOrderRepo{
-		createFn: func(ctx context.Context, order domain.OrderWithInformation) error {
-			return wantErr
+		createFn: func(ctx context.Context, order domain.OrderWithInformation) (bool, error) {
+			return false, wantErr
 		},
 	}
 	cache := &mockCache{}
@@ -85,6 +106,37 @@ func TestOrderService_CreateOrder_PropagatesError(t *testing.T) {
 	}
 }
 
+func TestOrderService_GetOrder_NotFoundIsTyped(t *testing.T) {
+	repo := &mockOrderRepo{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return nil, fmt.Errorf("order not found: %w", domain.ErrNotFound)
+		},
+	}
+
+	svc := NewOrderService(repo, &mockCache{})
+	_, err := svc.GetOrder(context.Background(), uuid.New())
+	if KindOf(err) != KindNotFound {
+		t.Fatalf("expected kind %s, got %s (%v)", KindNotFound, KindOf(err), err)
+	}
+	if AsError(err).Code != CodeOrderNotFound {
+		t.Fatalf("expected code %s, got %s", CodeOrderNotFound, AsError(err).Code)
+	}
+}
+
+func TestOrderService_GetOrder_UnexpectedErrorIsInternal(t *testing.T) {
+	repo := &mockOrderRepo{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return nil, errors.New("boom")
+		},
+	}
+
+	svc := NewOrderService(repo, &mockCache{})
+	_, err := svc.GetOrder(context.Background(), uuid.New())
+	if KindOf(err) != KindInternal {
+		t.Fatalf("expected kind %s, got %s", KindInternal, KindOf(err))
+	}
+}
+
 func TestOrderService_GetOrder_FromCache(t *testing.T) {
 	id := uuid.New()
 	order := sampleOrder(id)
@@ -153,6 +205,107 @@ func TestOrderService_GetOrder_FromRepo_SetsCache(t *testing.T) {
 	}
 }
 
+func TestOrderService_GetOrderParts_PartialMissSkipsCache(t *testing.T) {
+	id := uuid.New()
+	order := sampleOrder(id)
+
+	repo := &mockOrderRepo{
+		getPartsByIDFn: func(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
+			if include != domain.IncludePayment {
+				t.Fatalf("expected include %d, got %d", domain.IncludePayment, include)
+			}
+			return &order, nil
+		},
+	}
+	cache := &mockCache{}
+
+	svc := NewOrderService(repo, cache)
+	if _, err := svc.GetOrderParts(context.Background(), id, domain.IncludePayment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cache.setCalls != 0 {
+		t.Fatalf("expected partial order not to be cached, got %d Set calls", cache.setCalls)
+	}
+	if repo.getByIDCalls != 0 {
+		t.Fatalf("expected full GetByID not called, got %d", repo.getByIDCalls)
+	}
+}
+
+func TestOrderService_WatchOrders_ReceivesCreatedOrder(t *testing.T) {
+	svc := NewOrderService(&mockOrderRepo{}, &mockCache{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := svc.WatchOrders(ctx)
+
+	order := sampleOrder(uuid.New())
+	if err := svc.CreateOrder(context.Background(), order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case got := <-updates:
+		if got.ID != order.ID {
+			t.Fatalf("expected order %s, got %s", order.ID, got.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected created order to be published")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Fatalf("expected channel to be closed after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected channel to be closed after cancel")
+	}
+}
+
+func TestOrderService_CreateOrder_ErrorIsNotPublished(t *testing.T) {
+	repo := &mockOrderRepo{
+		createFn: func(ctx context.Context, order domain.OrderWithInformation) (bool, error) {
+			return false, errors.New("repo error")
+		},
+	}
+	svc := NewOrderService(repo, &mockCache{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	updates := svc.WatchOrders(ctx)
+
+	_ = svc.CreateOrder(context.Background(), sampleOrder(uuid.New()))
+
+	select {
+	case got := <-updates:
+		t.Fatalf("expected nothing published, got %s", got.ID)
+	default:
+	}
+}
+
+func TestOrderService_CreateOrder_DuplicateIsNotPublished(t *testing.T) {
+	repo := &mockOrderRepo{
+		createFn: func(ctx context.Context, order domain.OrderWithInformation) (bool, error) {
+			return false, nil
+		},
+	}
+	svc := NewOrderService(repo, &mockCache{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	updates := svc.WatchOrders(ctx)
+
+	if err := svc.CreateOrder(context.Background(), sampleOrder(uuid.New())); err != nil {
+		t.Fatalf("duplicate must not be an error, got %v", err)
+	}
+
+	select {
+	case got := <-updates:
+		t.Fatalf("expected redelivered order not to be published, got %s", got.ID)
+	default:
+	}
+}
+
 func sampleOrder(id uuid.UUID) domain.OrderWithInformation {
 	internalSignature := "sig"
 	deliveryService := "delivery"
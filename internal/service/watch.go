@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"web_demoservice/internal/domain"
+)
+
+const watchBufferSize = 64
+
+// watchers раздаёт сохранённые заказы подписчикам. Медленный подписчик не блокирует
+// запись: если его буфер заполнен, заказ для него пропускается.
+type watchers struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]chan domain.OrderWithInformation
+}
+
+func newWatchers() *watchers {
+	return &watchers{subs: make(map[int]chan domain.OrderWithInformation)}
+}
+
+func (w *watchers) subscribe(ctx context.Context) <-chan domain.OrderWithInformation {
+	ch := make(chan domain.OrderWithInformation, watchBufferSize)
+
+	w.mu.Lock()
+	id := w.nextID
+	w.nextID++
+	w.subs[id] = ch
+	w.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		delete(w.subs, id)
+		w.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (w *watchers) publish(order domain.OrderWithInformation) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ch := range w.subs {
+		select {
+		case ch <- order:
+		default:
+			slog.Warn("order watcher is lagging, dropping order", slog.String("order_id", order.ID.String()))
+		}
+	}
+}
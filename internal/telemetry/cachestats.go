@@ -0,0 +1,92 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"time"
+	"web_demoservice/internal/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cacheEntriesDesc = prometheus.NewDesc("cache_entries",
+		"Number of orders in the cache.", nil, nil)
+	cacheBytesDesc = prometheus.NewDesc("cache_bytes",
+		"Estimated size of cached orders in bytes (JSON size).", nil, nil)
+	cacheExpirationsDesc = prometheus.NewDesc("cache_expirations_total",
+		"Total number of entries removed by TTL.", nil, nil)
+	cacheEvictionsDesc = prometheus.NewDesc("cache_evictions_total",
+		"Total number of entries removed explicitly (erasure, admin evict or flush).", nil, nil)
+	cacheEntryAgeDesc = prometheus.NewDesc("cache_entry_age_seconds",
+		"Age of cached entries since they were stored.", nil, nil)
+
+	cacheEntryAgeBuckets = []float64{1, 10, 60, 300, 900, 1800, 3600, 6 * 3600, 24 * 3600}
+)
+
+// CacheSource — кэш, состояние которого читается в момент скрейпа.
+type CacheSource interface {
+	Stats() cache.Stats
+	RangeCreated(fn func(created time.Time))
+}
+
+type cacheCollector struct {
+	mu     sync.RWMutex
+	source CacheSource
+}
+
+var cacheStats = &cacheCollector{}
+
+// ObserveCache подключает кэш к метрикам cache_*.
+func ObserveCache(source CacheSource) {
+	cacheStats.mu.Lock()
+	cacheStats.source = source
+	cacheStats.mu.Unlock()
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheEntriesDesc
+	ch <- cacheBytesDesc
+	ch <- cacheExpirationsDesc
+	ch <- cacheEvictionsDesc
+	ch <- cacheEntryAgeDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	source := c.source
+	c.mu.RUnlock()
+	if source == nil {
+		return
+	}
+
+	stats := source.Stats()
+	ch <- prometheus.MustNewConstMetric(cacheEntriesDesc, prometheus.GaugeValue, float64(stats.Entries))
+	ch <- prometheus.MustNewConstMetric(cacheBytesDesc, prometheus.GaugeValue, float64(stats.Bytes))
+	ch <- prometheus.MustNewConstMetric(cacheExpirationsDesc, prometheus.CounterValue, float64(stats.Expired))
+	ch <- prometheus.MustNewConstMetric(cacheEvictionsDesc, prometheus.CounterValue, float64(stats.Evicted))
+
+	now := time.Now()
+	buckets := make([]uint64, len(cacheEntryAgeBuckets))
+	var (
+		total uint64
+		sum   float64
+	)
+	source.RangeCreated(func(created time.Time) {
+		age := now.Sub(created).Seconds()
+		total++
+		sum += age
+		if i := sort.SearchFloat64s(cacheEntryAgeBuckets, age); i < len(buckets) {
+			buckets[i]++
+		}
+	})
+
+	// Бакеты гистограммы кумулятивные.
+	counts := make(map[float64]uint64, len(cacheEntryAgeBuckets))
+	var cumulative uint64
+	for i, bound := range cacheEntryAgeBuckets {
+		cumulative += buckets[i]
+		counts[bound] = cumulative
+	}
+	ch <- prometheus.MustNewConstHistogram(cacheEntryAgeDesc, total, sum, counts)
+}
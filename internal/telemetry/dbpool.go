@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	dbPoolAcquiredDesc = prometheus.NewDesc("db_pool_acquired_conns",
+		"Connections currently acquired from the pool.", nil, nil)
+	dbPoolIdleDesc = prometheus.NewDesc("db_pool_idle_conns",
+		"Idle connections in the pool.", nil, nil)
+	dbPoolTotalDesc = prometheus.NewDesc("db_pool_total_conns",
+		"Total connections in the pool (acquired, idle and constructing).", nil, nil)
+	dbPoolMaxDesc = prometheus.NewDesc("db_pool_max_conns",
+		"Maximum size of the pool.", nil, nil)
+	dbPoolWaitCountDesc = prometheus.NewDesc("db_pool_wait_total",
+		"Total number of acquires that had to wait for a connection.", nil, nil)
+	dbPoolWaitDurationDesc = prometheus.NewDesc("db_pool_wait_seconds_total",
+		"Total time acquires spent waiting for a connection.", nil, nil)
+	dbPoolCanceledDesc = prometheus.NewDesc("db_pool_canceled_acquires_total",
+		"Total number of acquires canceled by context.", nil, nil)
+)
+
+// dbPoolCollector читает pgxpool.Stat в момент скрейпа.
+type dbPoolCollector struct {
+	mu   sync.RWMutex
+	stat func() *pgxpool.Stat
+}
+
+var dbPool = &dbPoolCollector{}
+
+// ObserveDBPool подключает статистику пула к метрикам db_pool_*.
+func ObserveDBPool(pool *pgxpool.Pool) {
+	dbPool.mu.Lock()
+	dbPool.stat = pool.Stat
+	dbPool.mu.Unlock()
+}
+
+func (c *dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbPoolAcquiredDesc
+	ch <- dbPoolIdleDesc
+	ch <- dbPoolTotalDesc
+	ch <- dbPoolMaxDesc
+	ch <- dbPoolWaitCountDesc
+	ch <- dbPoolWaitDurationDesc
+	ch <- dbPoolCanceledDesc
+}
+
+func (c *dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	stat := c.stat
+	c.mu.RUnlock()
+	if stat == nil {
+		return
+	}
+
+	s := stat()
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquiredDesc, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(dbPoolIdleDesc, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(dbPoolTotalDesc, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(dbPoolMaxDesc, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(dbPoolWaitCountDesc, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(dbPoolWaitDurationDesc, prometheus.CounterValue, s.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(dbPoolCanceledDesc, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
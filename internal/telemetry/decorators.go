@@ -5,9 +5,9 @@ import (
 	"errors"
 	"log/slog"
 	"web_demoservice/internal/domain"
+	"web_demoservice/internal/logging"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -16,19 +16,35 @@ import (
 type OrderService interface {
 	CreateOrder(ctx context.Context, order domain.OrderWithInformation) error
 	GetOrder(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	GetOrderParts(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error)
+	OrderVersion(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool)
+	ListOrders(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error)
+	WatchOrders(ctx context.Context) <-chan domain.OrderWithInformation
 	WarmUp(ctx context.Context) error
 }
 
 type OrderRepository interface {
-	Create(ctx context.Context, order domain.OrderWithInformation) error
+	Create(ctx context.Context, order domain.OrderWithInformation) (bool, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	GetPartsByID(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error)
 	GetAllLast24Hours(ctx context.Context) ([]domain.OrderWithInformation, error)
+	List(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error)
 	Ping(ctx context.Context) error
 }
 
+// CustomerRepository — выборки и анонимизация для запросов клиентов (GDPR).
+type CustomerRepository interface {
+	FindOrderIDsByCustomer(ctx context.Context, customerID string) ([]uuid.UUID, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	AnonymizeDeliveries(ctx context.Context, orderIDs []uuid.UUID) (int, error)
+}
+
 type Cache interface {
 	Set(ctx context.Context, key uuid.UUID, value domain.OrderWithInformation)
 	Get(ctx context.Context, key uuid.UUID) (*domain.OrderWithInformation, bool)
+	Version(ctx context.Context, key uuid.UUID) (*domain.OrderVersion, bool)
+	Delete(ctx context.Context, key uuid.UUID)
+	Hold(ctx context.Context, keys []uuid.UUID) (release func())
 }
 
 func WrapOrderService(next OrderService) OrderService {
@@ -39,6 +55,10 @@ func WrapOrderRepository(next OrderRepository) OrderRepository {
 	return &orderRepositoryTelemetry{next: next}
 }
 
+func WrapCustomerRepository(next CustomerRepository) CustomerRepository {
+	return &customerRepositoryTelemetry{next: next}
+}
+
 func WrapCache(next Cache) Cache {
 	return &cacheTelemetry{next: next}
 }
@@ -48,6 +68,7 @@ type orderServiceTelemetry struct {
 }
 
 func (t *orderServiceTelemetry) CreateOrder(ctx context.Context, order domain.OrderWithInformation) error {
+	ctx = logging.WithOrderID(ctx, order.ID.String())
 	ctx, span := otel.Tracer("service").Start(ctx, "OrderService.CreateOrder")
 	defer span.End()
 
@@ -55,13 +76,14 @@ func (t *orderServiceTelemetry) CreateOrder(ctx context.Context, order domain.Or
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Error("create order failed", slog.Any("error", err))
+		slog.ErrorContext(ctx, "create order failed", slog.Any("error", err))
 	}
 
 	return err
 }
 
 func (t *orderServiceTelemetry) GetOrder(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+	ctx = logging.WithOrderID(ctx, id.String())
 	ctx, span := otel.Tracer("service").Start(ctx, "OrderService.GetOrder")
 	defer span.End()
 
@@ -69,12 +91,56 @@ func (t *orderServiceTelemetry) GetOrder(ctx context.Context, id uuid.UUID) (*do
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Error("get order failed", slog.Any("error", err))
+		slog.ErrorContext(ctx, "get order failed", slog.Any("error", err))
+	}
+
+	return order, err
+}
+
+func (t *orderServiceTelemetry) GetOrderParts(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
+	ctx = logging.WithOrderID(ctx, id.String())
+	ctx, span := otel.Tracer("service").Start(ctx, "OrderService.GetOrderParts")
+	defer span.End()
+	span.SetAttributes(attribute.Int("order.include", int(include)))
+
+	order, err := t.next.GetOrderParts(ctx, id, include)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "get order parts failed", slog.Any("error", err))
 	}
 
 	return order, err
 }
 
+func (t *orderServiceTelemetry) OrderVersion(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool) {
+	ctx, span := otel.Tracer("service").Start(ctx, "OrderService.OrderVersion")
+	defer span.End()
+
+	return t.next.OrderVersion(ctx, id)
+}
+
+func (t *orderServiceTelemetry) ListOrders(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "OrderService.ListOrders")
+	defer span.End()
+
+	orders, err := t.next.ListOrders(ctx, after, limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "list orders failed", slog.Any("error", err))
+	}
+
+	return orders, err
+}
+
+func (t *orderServiceTelemetry) WatchOrders(ctx context.Context) <-chan domain.OrderWithInformation {
+	_, span := otel.Tracer("service").Start(ctx, "OrderService.WatchOrders")
+	defer span.End()
+
+	return t.next.WatchOrders(ctx)
+}
+
 func (t *orderServiceTelemetry) WarmUp(ctx context.Context) error {
 	ctx, span := otel.Tracer("service").Start(ctx, "OrderService.WarmUp")
 	defer span.End()
@@ -83,7 +149,7 @@ func (t *orderServiceTelemetry) WarmUp(ctx context.Context) error {
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Error("cache warm-up failed", slog.Any("error", err))
+		slog.ErrorContext(ctx, "cache warm-up failed", slog.Any("error", err))
 	}
 
 	return err
@@ -93,21 +159,21 @@ type orderRepositoryTelemetry struct {
 	next OrderRepository
 }
 
-func (t *orderRepositoryTelemetry) Create(ctx context.Context, order domain.OrderWithInformation) error {
+func (t *orderRepositoryTelemetry) Create(ctx context.Context, order domain.OrderWithInformation) (bool, error) {
 	ctx, span := otel.Tracer("repository").Start(ctx, "OrderRepository.Create")
 	defer span.End()
 
-	err := t.next.Create(ctx, order)
+	created, err := t.next.Create(ctx, order)
 	if err != nil {
 		IncStorageOp("db", "write", "error")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Error("repository create failed", slog.Any("error", err))
-		return err
+		slog.ErrorContext(ctx, "repository create failed", slog.Any("error", err))
+		return false, err
 	}
 
 	IncStorageOp("db", "write", "ok")
-	return nil
+	return created, nil
 }
 
 func (t *orderRepositoryTelemetry) GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
@@ -116,14 +182,36 @@ func (t *orderRepositoryTelemetry) GetByID(ctx context.Context, id uuid.UUID) (*
 
 	order, err := t.next.GetByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, domain.ErrNotFound) {
 			IncStorageOp("db", "read", "miss")
 			return nil, err
 		}
 		IncStorageOp("db", "read", "error")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Error("repository get failed", slog.Any("error", err))
+		slog.ErrorContext(ctx, "repository get failed", slog.Any("error", err))
+		return nil, err
+	}
+
+	IncStorageOp("db", "read", "ok")
+	return order, nil
+}
+
+func (t *orderRepositoryTelemetry) GetPartsByID(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
+	ctx, span := otel.Tracer("repository").Start(ctx, "OrderRepository.GetPartsByID")
+	defer span.End()
+	span.SetAttributes(attribute.Int("order.include", int(include)))
+
+	order, err := t.next.GetPartsByID(ctx, id, include)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			IncStorageOp("db", "read", "miss")
+			return nil, err
+		}
+		IncStorageOp("db", "read", "error")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "repository get parts failed", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -140,7 +228,24 @@ func (t *orderRepositoryTelemetry) GetAllLast24Hours(ctx context.Context) ([]dom
 		IncStorageOp("db", "read", "error")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Error("repository get all failed", slog.Any("error", err))
+		slog.ErrorContext(ctx, "repository get all failed", slog.Any("error", err))
+		return nil, err
+	}
+
+	IncStorageOp("db", "read", "ok")
+	return orders, nil
+}
+
+func (t *orderRepositoryTelemetry) List(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+	ctx, span := otel.Tracer("repository").Start(ctx, "OrderRepository.List")
+	defer span.End()
+
+	orders, err := t.next.List(ctx, after, limit)
+	if err != nil {
+		IncStorageOp("db", "read", "error")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "repository list failed", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -156,7 +261,7 @@ func (t *orderRepositoryTelemetry) Ping(ctx context.Context) error {
 		SetRepositoryUp("order_postgres", false)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Warn("repository ping failed", slog.Any("error", err))
+		slog.WarnContext(ctx, "repository ping failed", slog.Any("error", err))
 		return err
 	}
 
@@ -164,6 +269,67 @@ func (t *orderRepositoryTelemetry) Ping(ctx context.Context) error {
 	return nil
 }
 
+type customerRepositoryTelemetry struct {
+	next CustomerRepository
+}
+
+func (t *customerRepositoryTelemetry) FindOrderIDsByCustomer(ctx context.Context, customerID string) ([]uuid.UUID, error) {
+	ctx, span := otel.Tracer("repository").Start(ctx, "CustomerRepository.FindOrderIDsByCustomer")
+	defer span.End()
+
+	ids, err := t.next.FindOrderIDsByCustomer(ctx, customerID)
+	if err != nil {
+		IncStorageOp("db", "read", "error")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "repository find customer orders failed", slog.Any("error", err))
+		return nil, err
+	}
+
+	IncStorageOp("db", "read", "ok")
+	span.SetAttributes(attribute.Int("customer.orders", len(ids)))
+	return ids, nil
+}
+
+func (t *customerRepositoryTelemetry) GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+	ctx, span := otel.Tracer("repository").Start(ctx, "CustomerRepository.GetByID")
+	defer span.End()
+
+	order, err := t.next.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			IncStorageOp("db", "read", "miss")
+			return nil, err
+		}
+		IncStorageOp("db", "read", "error")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "repository get failed", slog.Any("error", err))
+		return nil, err
+	}
+
+	IncStorageOp("db", "read", "ok")
+	return order, nil
+}
+
+func (t *customerRepositoryTelemetry) AnonymizeDeliveries(ctx context.Context, orderIDs []uuid.UUID) (int, error) {
+	ctx, span := otel.Tracer("repository").Start(ctx, "CustomerRepository.AnonymizeDeliveries")
+	defer span.End()
+
+	anonymized, err := t.next.AnonymizeDeliveries(ctx, orderIDs)
+	if err != nil {
+		IncStorageOp("db", "write", "error")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "repository anonymize failed", slog.Any("error", err))
+		return 0, err
+	}
+
+	IncStorageOp("db", "write", "ok")
+	span.SetAttributes(attribute.Int("customer.anonymized", anonymized))
+	return anonymized, nil
+}
+
 type cacheTelemetry struct {
 	next Cache
 }
@@ -191,3 +357,34 @@ func (t *cacheTelemetry) Get(ctx context.Context, key uuid.UUID) (*domain.OrderW
 	IncStorageOp("cache", "read", "hit")
 	return value, true
 }
+
+func (t *cacheTelemetry) Version(ctx context.Context, key uuid.UUID) (*domain.OrderVersion, bool) {
+	ctx, span := otel.Tracer("cache").Start(ctx, "cache.version")
+	defer span.End()
+
+	version, ok := t.next.Version(ctx, key)
+	span.SetAttributes(attribute.Bool("cache.hit", ok))
+	if !ok {
+		IncStorageOp("cache", "read", "miss")
+		return nil, false
+	}
+
+	IncStorageOp("cache", "read", "hit")
+	return version, true
+}
+
+func (t *cacheTelemetry) Delete(ctx context.Context, key uuid.UUID) {
+	ctx, span := otel.Tracer("cache").Start(ctx, "cache.delete")
+	defer span.End()
+
+	t.next.Delete(ctx, key)
+	IncStorageOp("cache", "delete", "ok")
+}
+
+func (t *cacheTelemetry) Hold(ctx context.Context, keys []uuid.UUID) func() {
+	ctx, span := otel.Tracer("cache").Start(ctx, "cache.hold")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("cache.keys", len(keys)))
+	return t.next.Hold(ctx, keys)
+}
@@ -1,13 +1,17 @@
 package telemetry
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -26,6 +30,28 @@ var (
 		},
 		[]string{"method", "path", "status"},
 	)
+	grpcRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_total",
+			Help: "Total number of gRPC requests.",
+		},
+		[]string{"method", "code"},
+	)
+	grpcRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_request_duration_seconds",
+			Help:    "gRPC request duration in seconds.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "code"},
+	)
+	httpThrottledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_requests_throttled_total",
+			Help: "Total number of HTTP requests rejected by the rate limiter.",
+		},
+		[]string{"group"},
+	)
 	kafkaMessagesTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "kafka_messages_total",
@@ -39,6 +65,48 @@ var (
 			Help: "Total number of DLQ publish failures.",
 		},
 	)
+	kafkaConsumerLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kafka_consumer_lag",
+			Help: "Consumer group lag in records per partition.",
+		},
+		[]string{"topic", "partition"},
+	)
+	kafkaProcessedLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kafka_processed_latency_seconds",
+			Help:    "Time from Kafka record timestamp until the record is processed (stored or sent to DLQ); commit happens later.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+		},
+	)
+	kafkaStageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kafka_stage_duration_seconds",
+			Help:    "Kafka record handling duration per stage (decode, validate, map, persist, dlq).",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"stage"},
+	)
+	kafkaFetchBatchSize = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kafka_fetch_batch_size",
+			Help:    "Number of records returned by one Kafka poll.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+	)
+	kafkaRebalancesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_rebalances_total",
+			Help: "Total number of consumer group rebalance events (assigned, revoked, lost).",
+		},
+		[]string{"event"},
+	)
+	kafkaConsumerPaused = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "kafka_consumer_paused",
+			Help: "Whether consumption is paused because storage is unavailable (1 = paused).",
+		},
+	)
 	storageOpsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "storage_ops_total",
@@ -46,6 +114,42 @@ var (
 		},
 		[]string{"store", "op", "result"},
 	)
+	dbQueryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Postgres statement duration by stable query name.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"query", "result"},
+	)
+	breakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "circuit_breaker_state",
+			Help: "Circuit breaker state (0 = closed, 1 = half-open, 2 = open).",
+		},
+		[]string{"name"},
+	)
+	breakerRejectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "circuit_breaker_rejected_total",
+			Help: "Total number of calls rejected by an open circuit breaker.",
+		},
+		[]string{"name"},
+	)
+	auditEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "audit_events_total",
+			Help: "Total number of audit events by result (written, dropped, failed).",
+		},
+		[]string{"result"},
+	)
+	configReloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "config_reloads_total",
+			Help: "Total number of config reloads by result (applied, unchanged, failed).",
+		},
+		[]string{"result"},
+	)
 	repositoryUp = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "repository_up",
@@ -56,12 +160,35 @@ var (
 )
 
 func init() {
+	// Go runtime по runtime/metrics: паузы GC, горутины, классы памяти кучи — в дополнение
+	// к go_memstats_* стандартного коллектора.
+	prometheus.Unregister(collectors.NewGoCollector())
+	prometheus.MustRegister(collectors.NewGoCollector(collectors.WithGoCollectorRuntimeMetrics(
+		collectors.MetricsGC, collectors.MetricsMemory, collectors.MetricsScheduler,
+	)))
+
 	prometheus.MustRegister(
 		httpRequestsTotal,
 		httpRequestDuration,
+		grpcRequestsTotal,
+		grpcRequestDuration,
+		httpThrottledTotal,
 		kafkaMessagesTotal,
 		kafkaDLQPublishFailuresTotal,
+		kafkaConsumerLag,
+		kafkaProcessedLatency,
+		kafkaStageDuration,
+		kafkaFetchBatchSize,
+		kafkaRebalancesTotal,
+		kafkaConsumerPaused,
 		storageOpsTotal,
+		dbQueryDuration,
+		dbPool,
+		cacheStats,
+		breakerState,
+		breakerRejectedTotal,
+		auditEventsTotal,
+		configReloadsTotal,
 		repositoryUp,
 	)
 }
@@ -91,6 +218,30 @@ func MetricsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+func MetricsUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observeGRPC(info.FullMethod, err, start)
+	return resp, err
+}
+
+func MetricsStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	observeGRPC(info.FullMethod, err, start)
+	return err
+}
+
+func observeGRPC(method string, err error, start time.Time) {
+	code := status.Code(err).String()
+	grpcRequestsTotal.WithLabelValues(method, code).Inc()
+	grpcRequestDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+func IncThrottled(group string) {
+	httpThrottledTotal.WithLabelValues(group).Inc()
+}
+
 func IncKafkaResult(result string) {
 	kafkaMessagesTotal.WithLabelValues(result).Inc()
 }
@@ -99,10 +250,63 @@ func IncKafkaDLQPublishFailure() {
 	kafkaDLQPublishFailuresTotal.Inc()
 }
 
+// ResetKafkaLag убирает лаг партиций, которые больше не принадлежат группе.
+func ResetKafkaLag() {
+	kafkaConsumerLag.Reset()
+}
+
+func SetKafkaLag(topic string, partition int32, lag int64) {
+	kafkaConsumerLag.WithLabelValues(topic, strconv.Itoa(int(partition))).Set(float64(lag))
+}
+
+func ObserveKafkaProcessed(d time.Duration) {
+	kafkaProcessedLatency.Observe(d.Seconds())
+}
+
+func ObserveKafkaStage(stage string, d time.Duration) {
+	kafkaStageDuration.WithLabelValues(stage).Observe(d.Seconds())
+}
+
+func ObserveKafkaFetchBatch(records int) {
+	kafkaFetchBatchSize.Observe(float64(records))
+}
+
+func IncKafkaRebalance(event string) {
+	kafkaRebalancesTotal.WithLabelValues(event).Inc()
+}
+
+func SetKafkaPaused(paused bool) {
+	value := 0.0
+	if paused {
+		value = 1.0
+	}
+	kafkaConsumerPaused.Set(value)
+}
+
 func IncStorageOp(store, op, result string) {
 	storageOpsTotal.WithLabelValues(store, op, result).Inc()
 }
 
+func ObserveDBQuery(query, result string, d time.Duration) {
+	dbQueryDuration.WithLabelValues(query, result).Observe(d.Seconds())
+}
+
+func SetBreakerState(name string, state int) {
+	breakerState.WithLabelValues(name).Set(float64(state))
+}
+
+func IncBreakerRejected(name string) {
+	breakerRejectedTotal.WithLabelValues(name).Inc()
+}
+
+func AddAuditEvents(result string, n int) {
+	auditEventsTotal.WithLabelValues(result).Add(float64(n))
+}
+
+func IncConfigReload(result string) {
+	configReloadsTotal.WithLabelValues(result).Inc()
+}
+
 func SetRepositoryUp(repo string, up bool) {
 	value := 0.0
 	if up {
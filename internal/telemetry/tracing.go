@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 	"web_demoservice/internal/config"
 
@@ -33,9 +34,7 @@ func SetupTracing(ctx context.Context, cfg config.TelemetryConfig) (func(context
 	if cfg.ServiceName == "" {
 		cfg.ServiceName = "web_demoservice"
 	}
-	if cfg.SampleRatio <= 0 || cfg.SampleRatio > 1 {
-		cfg.SampleRatio = 1
-	}
+	SetSampleRatio(cfg.SampleRatio)
 
 	res, err := resource.New(
 		ctx,
@@ -63,7 +62,7 @@ func SetupTracing(ctx context.Context, cfg config.TelemetryConfig) (func(context
 	tp := sdktrace.NewTracerProvider(
 		sdktrace.WithResource(res),
 		sdktrace.WithBatcher(exp),
-		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
 	)
 
 	otel.SetTracerProvider(tp)
@@ -74,3 +73,34 @@ func SetupTracing(ctx context.Context, cfg config.TelemetryConfig) (func(context
 
 	return tp.Shutdown, nil
 }
+
+// sampler — доля сэмплирования, которую можно менять без пересоздания TracerProvider.
+var sampler = &ratioSampler{}
+
+// SetSampleRatio меняет долю сэмплирования корневых спанов; 0 и значения вне (0, 1]
+// означают «сэмплировать всё».
+func SetSampleRatio(ratio float64) {
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	next := sdktrace.TraceIDRatioBased(ratio)
+	sampler.current.Store(&next)
+}
+
+type ratioSampler struct {
+	current atomic.Pointer[sdktrace.Sampler]
+}
+
+func (s *ratioSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if current := s.current.Load(); current != nil {
+		return (*current).ShouldSample(p)
+	}
+	return sdktrace.AlwaysSample().ShouldSample(p)
+}
+
+func (s *ratioSampler) Description() string {
+	if current := s.current.Load(); current != nil {
+		return (*current).Description()
+	}
+	return sdktrace.AlwaysSample().Description()
+}
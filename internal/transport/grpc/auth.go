@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/grpc/pb/orderv1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requiredScope возвращает скоуп метода; health и reflection доступны без аутентификации.
+func requiredScope(fullMethod string) (auth.Scope, bool) {
+	if strings.HasPrefix(fullMethod, "/"+orderv1.OrderService_ServiceDesc.ServiceName+"/") {
+		return auth.ScopeOrdersRead, true
+	}
+	return "", false
+}
+
+func AuthUnaryInterceptor(a *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, a, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func AuthStreamInterceptor(a *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), a, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, a *auth.Authenticator, fullMethod string) (context.Context, error) {
+	scope, ok := requiredScope(fullMethod)
+	if !ok {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	identity, err := a.Authenticate(firstValue(md, "authorization"), firstValue(md, "x-api-key"))
+	if err != nil {
+		if !errors.Is(err, auth.ErrNoCredentials) {
+			slog.Warn("authentication failed", slog.String("method", fullMethod), slog.Any("error", err))
+		}
+		return ctx, statusFromError(service.Unauthenticated(service.CodeUnauthenticated, "valid API key or bearer token required", err))
+	}
+	if !identity.HasScope(scope) {
+		return ctx, statusFromError(service.Forbidden(service.CodeInsufficientScope, "scope "+scope+" required"))
+	}
+
+	return auth.WithIdentity(ctx, identity), nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"web_demoservice/internal/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func LoggingUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	slog.Info("grpc request", logAttrs(ctx, info.FullMethod, err, start)...)
+
+	return resp, err
+}
+
+func LoggingStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	slog.Info("grpc stream", logAttrs(ss.Context(), info.FullMethod, err, start)...)
+
+	return err
+}
+
+func logAttrs(ctx context.Context, method string, err error, start time.Time) []any {
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if identity := auth.FromContext(ctx); identity != nil {
+		attrs = append(attrs, slog.String("caller", identity.Subject), slog.String("auth_method", string(identity.Method)))
+	}
+	return attrs
+}
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/transport/grpc/pb/orderv1"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func mapOrder(order *domain.OrderWithInformation) *orderv1.Order {
+	items := make([]*orderv1.Item, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, &orderv1.Item{
+			ChrtId:      getValue(item.ChrtID),
+			TrackNumber: item.TrackNumber,
+			Price:       item.Price,
+			Rid:         item.RID,
+			Name:        item.Name,
+			Sale:        getValue(item.Sale),
+			Size:        getValue(item.Size),
+			TotalPrice:  item.TotalPrice,
+			NmId:        item.NmID,
+			Brand:       item.Brand,
+			Status:      int32(item.Status),
+		})
+	}
+
+	return &orderv1.Order{
+		OrderUid:          order.ID.String(),
+		TrackNumber:       order.TrackNumber,
+		Entry:             order.Entry,
+		Locale:            order.Locale,
+		InternalSignature: getValue(order.InternalSignature),
+		CustomerId:        order.CustomerID,
+		DeliveryService:   getValue(order.DeliveryService),
+		Shardkey:          order.ShardKey,
+		SmId:              getValue(order.SmID),
+		DateCreated:       timestamppb.New(order.DateCreated),
+		OofShard:          order.OofShard,
+		Delivery: &orderv1.Delivery{
+			Name:    order.Delivery.Name,
+			Phone:   order.Delivery.Phone,
+			Zip:     order.Delivery.Zip,
+			City:    order.Delivery.City,
+			Address: order.Delivery.Address,
+			Region:  getValue(order.Delivery.Region),
+			Email:   order.Delivery.Email,
+		},
+		Payment: &orderv1.Payment{
+			Transaction:  order.Payment.Transaction,
+			RequestId:    getValue(order.Payment.RequestID),
+			Currency:     order.Payment.Currency,
+			Provider:     order.Payment.Provider,
+			Amount:       order.Payment.Amount,
+			PaymentDt:    order.Payment.PaymentDt,
+			Bank:         order.Payment.Bank.Name,
+			DeliveryCost: order.Payment.DeliveryCost,
+			GoodsTotal:   order.Payment.GoodsTotal,
+			CustomFee:    order.Payment.CustomFee,
+		},
+		Items: items,
+	}
+}
+
+// page_token — base64(RFC3339Nano date_created + "|" + order_id) последнего заказа страницы.
+func encodePageToken(cursor domain.OrderCursor) string {
+	raw := cursor.DateCreated.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token string) (*domain.OrderCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errors.New("malformed page token")
+	}
+
+	dateCreated, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, err
+	}
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OrderCursor{DateCreated: dateCreated, ID: orderID}, nil
+}
+
+// Вспомогательная функция для безопасного получения значений из указателей
+func getValue[T any](ptr *T) T {
+	if ptr == nil {
+		var zero T
+		return zero
+	}
+	return *ptr
+}
@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/pii"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/grpc/pb/orderv1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+	maxBatchSize    = 500
+)
+
+type OrderService interface {
+	GetOrder(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	ListOrders(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error)
+	WatchOrders(ctx context.Context) <-chan domain.OrderWithInformation
+}
+
+// NewServer собирает gRPC сервер с OrderService, health checking и reflection.
+func NewServer(orders orderv1.OrderServiceServer, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	orderv1.RegisterOrderServiceServer(server, orders)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(orderv1.OrderService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
+
+type OrderServer struct {
+	orderv1.UnimplementedOrderServiceServer
+	service OrderService
+	masker  *pii.Masker
+}
+
+func NewOrderServer(service OrderService, masker *pii.Masker) *OrderServer {
+	return &OrderServer{
+		service: service,
+		masker:  masker,
+	}
+}
+
+func (s *OrderServer) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
+	id, err := parseOrderUID(req.GetOrderUid())
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.service.GetOrder(ctx, id)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &orderv1.GetOrderResponse{Order: s.mapOrder(ctx, order)}, nil
+}
+
+func (s *OrderServer) ListOrders(ctx context.Context, req *orderv1.ListOrdersRequest) (*orderv1.ListOrdersResponse, error) {
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must be non-negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	after, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	orders, err := s.service.ListOrders(ctx, after, pageSize)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	resp := &orderv1.ListOrdersResponse{Orders: make([]*orderv1.Order, 0, len(orders))}
+	for i := range orders {
+		resp.Orders = append(resp.Orders, s.mapOrder(ctx, &orders[i]))
+	}
+	if len(orders) == pageSize {
+		last := orders[len(orders)-1]
+		resp.NextPageToken = encodePageToken(domain.OrderCursor{DateCreated: last.DateCreated, ID: last.ID})
+	}
+
+	return resp, nil
+}
+
+func (s *OrderServer) BatchGetOrders(ctx context.Context, req *orderv1.BatchGetOrdersRequest) (*orderv1.BatchGetOrdersResponse, error) {
+	if len(req.GetOrderUids()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d order_uids per request", maxBatchSize)
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.GetOrderUids()))
+	for _, raw := range req.GetOrderUids() {
+		id, err := parseOrderUID(raw)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	resp := &orderv1.BatchGetOrdersResponse{Orders: make([]*orderv1.Order, 0, len(ids))}
+	for _, id := range ids {
+		order, err := s.service.GetOrder(ctx, id)
+		if err != nil {
+			if service.KindOf(err) == service.KindNotFound {
+				resp.NotFound = append(resp.NotFound, id.String())
+				continue
+			}
+			return nil, statusFromError(err)
+		}
+		resp.Orders = append(resp.Orders, s.mapOrder(ctx, order))
+	}
+
+	return resp, nil
+}
+
+func (s *OrderServer) WatchOrders(_ *orderv1.WatchOrdersRequest, stream orderv1.OrderService_WatchOrdersServer) error {
+	for order := range s.service.WatchOrders(stream.Context()) {
+		if err := stream.Send(&orderv1.WatchOrdersResponse{Order: s.mapOrder(stream.Context(), &order)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mapOrder маскирует доставку, если у клиента нет скоупа PII.
+func (s *OrderServer) mapOrder(ctx context.Context, order *domain.OrderWithInformation) *orderv1.Order {
+	if !auth.FromContext(ctx).HasScope(auth.ScopeOrdersReadPII) {
+		order = s.masker.Order(order)
+	}
+	return mapOrder(order)
+}
+
+// statusFromError отображает ошибку сервиса в gRPC статус; код ошибки уходит в сообщение.
+func statusFromError(err error) error {
+	svcErr := service.AsError(err)
+
+	code := codes.Internal
+	switch svcErr.Kind {
+	case service.KindNotFound:
+		code = codes.NotFound
+	case service.KindInvalidInput:
+		code = codes.InvalidArgument
+	case service.KindConflict:
+		code = codes.AlreadyExists
+	case service.KindUnavailable:
+		code = codes.Unavailable
+	case service.KindUnauthenticated:
+		code = codes.Unauthenticated
+	case service.KindForbidden:
+		code = codes.PermissionDenied
+	case service.KindRateLimited, service.KindTooLarge:
+		code = codes.ResourceExhausted
+	}
+
+	return status.Errorf(code, "%s: %s", svcErr.Code, svcErr.Message)
+}
+
+func parseOrderUID(raw string) (uuid.UUID, error) {
+	if raw == "" {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "order_uid is required")
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid order_uid")
+	}
+
+	return id, nil
+}
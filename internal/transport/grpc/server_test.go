@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/pii"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/grpc/pb/orderv1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockOrderService struct {
+	getOrderFn   func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	listOrdersFn func(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error)
+}
+
+func (m *mockOrderService) GetOrder(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+	return m.getOrderFn(ctx, id)
+}
+
+func (m *mockOrderService) ListOrders(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+	return m.listOrdersFn(ctx, after, limit)
+}
+
+func (m *mockOrderService) WatchOrders(ctx context.Context) <-chan domain.OrderWithInformation {
+	ch := make(chan domain.OrderWithInformation)
+	close(ch)
+	return ch
+}
+
+func TestOrderServer_GetOrder_InvalidArgument(t *testing.T) {
+	s := NewOrderServer(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			t.Fatalf("service should not be called")
+			return nil, nil
+		},
+	}, testMasker)
+
+	_, err := s.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderUid: "bad"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected %s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestOrderServer_GetOrder_NotFound(t *testing.T) {
+	s := NewOrderServer(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return nil, service.NotFound(service.CodeOrderNotFound, "order not found", nil)
+		},
+	}, testMasker)
+
+	_, err := s.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderUid: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected %s, got %s", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestOrderServer_BatchGetOrders_CollectsNotFound(t *testing.T) {
+	found := uuid.New()
+	missing := uuid.New()
+	s := NewOrderServer(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			if id == missing {
+				return nil, service.NotFound(service.CodeOrderNotFound, "order not found", nil)
+			}
+			order := domain.OrderWithInformation{}
+			order.ID = id
+			return &order, nil
+		},
+	}, testMasker)
+
+	resp, err := s.BatchGetOrders(context.Background(), &orderv1.BatchGetOrdersRequest{
+		OrderUids: []string{found.String(), missing.String()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Orders) != 1 || resp.Orders[0].OrderUid != found.String() {
+		t.Fatalf("expected order %s, got %+v", found, resp.Orders)
+	}
+	if len(resp.NotFound) != 1 || resp.NotFound[0] != missing.String() {
+		t.Fatalf("expected not_found %s, got %v", missing, resp.NotFound)
+	}
+}
+
+func TestOrderServer_ListOrders_PageToken(t *testing.T) {
+	last := domain.OrderWithInformation{}
+	last.ID = uuid.New()
+	last.DateCreated = time.Now().UTC()
+
+	var gotAfter *domain.OrderCursor
+	s := NewOrderServer(&mockOrderService{
+		listOrdersFn: func(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+			gotAfter = after
+			if limit != 1 {
+				t.Fatalf("expected limit 1, got %d", limit)
+			}
+			return []domain.OrderWithInformation{last}, nil
+		},
+	}, testMasker)
+
+	resp, err := s.ListOrders(context.Background(), &orderv1.ListOrdersRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAfter != nil {
+		t.Fatalf("expected first page without cursor")
+	}
+	if resp.NextPageToken == "" {
+		t.Fatalf("expected next_page_token on full page")
+	}
+
+	if _, err = s.ListOrders(context.Background(), &orderv1.ListOrdersRequest{PageSize: 1, PageToken: resp.NextPageToken}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAfter == nil || gotAfter.ID != last.ID || !gotAfter.DateCreated.Equal(last.DateCreated) {
+		t.Fatalf("expected cursor from last order, got %+v", gotAfter)
+	}
+}
+
+func TestOrderServer_ListOrders_InvalidPageToken(t *testing.T) {
+	s := NewOrderServer(&mockOrderService{
+		listOrdersFn: func(ctx context.Context, after *domain.OrderCursor, limit int) ([]domain.OrderWithInformation, error) {
+			return nil, errors.New("should not be called")
+		},
+	}, testMasker)
+
+	_, err := s.ListOrders(context.Background(), &orderv1.ListOrdersRequest{PageToken: "!!!"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected %s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+var testMasker = func() *pii.Masker {
+	m, err := pii.NewMasker(nil)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}()
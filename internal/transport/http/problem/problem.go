@@ -0,0 +1,87 @@
+package problem
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"web_demoservice/internal/service"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ContentType = "application/problem+json"
+
+const typePrefix = "urn:web_demoservice:problem:"
+
+// Problem — тело ответа об ошибке по RFC 7807.
+type Problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Code     string               `json:"code"`
+	TraceID  string               `json:"trace_id,omitempty"`
+	Errors   []service.FieldError `json:"errors,omitempty"`
+}
+
+func StatusOf(kind service.Kind) int {
+	switch kind {
+	case service.KindNotFound:
+		return http.StatusNotFound
+	case service.KindInvalidInput:
+		return http.StatusBadRequest
+	case service.KindConflict:
+		return http.StatusConflict
+	case service.KindUnavailable:
+		return http.StatusServiceUnavailable
+	case service.KindUnauthenticated:
+		return http.StatusUnauthorized
+	case service.KindForbidden:
+		return http.StatusForbidden
+	case service.KindRateLimited:
+		return http.StatusTooManyRequests
+	case service.KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FromError строит Problem из ошибки сервиса. Текст внутренних ошибок клиенту не отдаётся.
+func FromError(r *http.Request, err error) Problem {
+	svcErr := service.AsError(err)
+	status := StatusOf(svcErr.Kind)
+
+	p := Problem{
+		Type:     typePrefix + svcErr.Code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   svcErr.Message,
+		Instance: r.URL.Path,
+		Code:     svcErr.Code,
+		Errors:   svcErr.Fields,
+	}
+	if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+		p.TraceID = sc.TraceID().String()
+	}
+
+	return p
+}
+
+// Write отвечает application/problem+json, соответствующим ошибке.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	WriteProblem(w, FromError(r, err))
+}
+
+func WriteProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if p.Status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "5")
+	}
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		slog.Error("failed to encode problem response", slog.Any("error", err))
+	}
+}
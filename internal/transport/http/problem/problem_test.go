@@ -0,0 +1,62 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web_demoservice/internal/service"
+)
+
+func TestWrite_MapsKindToStatus(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{service.NotFound(service.CodeOrderNotFound, "order not found", nil), http.StatusNotFound, service.CodeOrderNotFound},
+		{service.InvalidInput(service.CodeInvalidOrderID, "invalid order_id"), http.StatusBadRequest, service.CodeInvalidOrderID},
+		{service.Conflict(service.CodeOrderConflict, "conflict", nil), http.StatusConflict, service.CodeOrderConflict},
+		{service.Unavailable(service.CodeStorageUnavailable, "down", nil), http.StatusServiceUnavailable, service.CodeStorageUnavailable},
+		{service.Unauthenticated(service.CodeUnauthenticated, "no credentials", nil), http.StatusUnauthorized, service.CodeUnauthenticated},
+		{service.Forbidden(service.CodeInsufficientScope, "scope required"), http.StatusForbidden, service.CodeInsufficientScope},
+		{service.RateLimited(service.CodeRateLimited, "too many requests"), http.StatusTooManyRequests, service.CodeRateLimited},
+		{errors.New("boom"), http.StatusInternalServerError, service.CodeInternal},
+	}
+
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		Write(rec, httptest.NewRequest(http.MethodGet, "/api/v1/order/x", nil), tc.err)
+
+		if rec.Code != tc.status {
+			t.Fatalf("%v: expected status %d, got %d", tc.err, tc.status, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+			t.Fatalf("expected content type %s, got %s", ContentType, ct)
+		}
+
+		var got Problem
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to decode problem: %v", err)
+		}
+		if got.Code != tc.code || got.Status != tc.status || got.Instance != "/api/v1/order/x" {
+			t.Fatalf("unexpected problem: %+v", got)
+		}
+	}
+}
+
+func TestWrite_IncludesFieldErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := service.InvalidInput(service.CodeInvalidOrderID, "invalid order_id",
+		service.FieldError{Field: "order_id", Reason: "must be a valid UUID"})
+	Write(rec, httptest.NewRequest(http.MethodGet, "/", nil), err)
+
+	var got Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].Field != "order_id" {
+		t.Fatalf("expected field error for order_id, got %+v", got.Errors)
+	}
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+	"web_demoservice/internal/domain"
+)
+
+type AuditEventDTO struct {
+	ID         int64          `json:"id"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Actor      string         `json:"actor"`
+	AuthMethod string         `json:"auth_method,omitempty"`
+	Action     string         `json:"action"`
+	OrderUID   string         `json:"order_uid,omitempty"`
+	Fields     []string       `json:"fields,omitempty"`
+	TraceID    string         `json:"trace_id,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+// AuditEventsDTO: next_before передаётся в ?before= для следующей страницы.
+type AuditEventsDTO struct {
+	Events     []AuditEventDTO `json:"events"`
+	NextBefore int64           `json:"next_before,omitempty"`
+}
+
+func MapToAuditEventsDTO(events []domain.AuditEvent, limit int) AuditEventsDTO {
+	out := AuditEventsDTO{Events: make([]AuditEventDTO, 0, len(events))}
+	for _, e := range events {
+		event := AuditEventDTO{
+			ID:         e.ID,
+			OccurredAt: e.OccurredAt,
+			Actor:      e.Actor,
+			AuthMethod: e.AuthMethod,
+			Action:     e.Action,
+			Fields:     e.Fields,
+			TraceID:    e.TraceID,
+			Details:    e.Details,
+		}
+		if e.OrderID != nil {
+			event.OrderUID = e.OrderID.String()
+		}
+		out.Events = append(out.Events, event)
+	}
+	if limit > 0 && len(events) == limit {
+		out.NextBefore = events[len(events)-1].ID
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+	"web_demoservice/internal/domain"
+)
+
+type CacheEntryDTO struct {
+	OrderUID     string    `json:"order_uid"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastAccessAt time.Time `json:"last_access_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	AgeSeconds   float64   `json:"age_seconds"`
+	SizeBytes    int       `json:"size_bytes"`
+	ETag         string    `json:"etag"`
+}
+
+// CacheEntriesDTO: next_after передаётся в ?after= для следующей страницы.
+type CacheEntriesDTO struct {
+	Entries   []CacheEntryDTO `json:"entries"`
+	Total     int             `json:"total"`
+	NextAfter string          `json:"next_after,omitempty"`
+}
+
+type CacheFlushDTO struct {
+	Evicted int `json:"evicted"`
+}
+
+func MapToCacheEntryDTO(entry domain.CacheEntry, now time.Time) CacheEntryDTO {
+	return CacheEntryDTO{
+		OrderUID:     entry.OrderID.String(),
+		CreatedAt:    entry.CreatedAt,
+		LastAccessAt: entry.LastAccess,
+		ExpiresAt:    entry.ExpiresAt,
+		AgeSeconds:   now.Sub(entry.CreatedAt).Seconds(),
+		SizeBytes:    entry.Size,
+		ETag:         entry.ETag,
+	}
+}
+
+// MapToCacheEntriesDTO: page — страница из total записей; если она полная, next_after
+// указывает на её последний ключ.
+func MapToCacheEntriesDTO(page []domain.CacheEntry, total, limit int, now time.Time) CacheEntriesDTO {
+	out := CacheEntriesDTO{Entries: make([]CacheEntryDTO, 0, len(page)), Total: total}
+	for _, entry := range page {
+		out.Entries = append(out.Entries, MapToCacheEntryDTO(entry, now))
+	}
+	if limit > 0 && len(page) == limit {
+		out.NextAfter = page[len(page)-1].OrderID.String()
+	}
+	return out
+}
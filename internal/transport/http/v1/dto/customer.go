@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+	"web_demoservice/internal/domain"
+)
+
+// CustomerExportDTO — выгрузка данных клиента без маскирования PII.
+type CustomerExportDTO struct {
+	CustomerID string                    `json:"customer_id"`
+	ExportedAt time.Time                 `json:"exported_at"`
+	Orders     []OrderWithInformationDTO `json:"orders"`
+}
+
+type CustomerErasureDTO struct {
+	CustomerID string   `json:"customer_id"`
+	OrderUIDs  []string `json:"order_uids"`
+	Anonymized int      `json:"anonymized"`
+}
+
+func MapToCustomerExportDTO(export *domain.CustomerExport) CustomerExportDTO {
+	out := CustomerExportDTO{
+		CustomerID: export.CustomerID,
+		ExportedAt: export.ExportedAt,
+		Orders:     make([]OrderWithInformationDTO, 0, len(export.Orders)),
+	}
+	for i := range export.Orders {
+		out.Orders = append(out.Orders, MapToOrderDTO(&export.Orders[i]))
+	}
+	return out
+}
+
+func MapToCustomerErasureDTO(erasure *domain.CustomerErasure) CustomerErasureDTO {
+	out := CustomerErasureDTO{
+		CustomerID: erasure.CustomerID,
+		OrderUIDs:  make([]string, 0, len(erasure.OrderIDs)),
+		Anonymized: erasure.Anonymized,
+	}
+	for _, id := range erasure.OrderIDs {
+		out.OrderUIDs = append(out.OrderUIDs, id.String())
+	}
+	return out
+}
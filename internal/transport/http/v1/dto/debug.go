@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+	"web_demoservice/internal/cache"
+)
+
+// StatusDTO — состояние процесса для /debug/status.
+type StatusDTO struct {
+	Build         BuildDTO          `json:"build"`
+	ConfigHash    string            `json:"config_hash"`
+	StartedAt     time.Time         `json:"started_at"`
+	UptimeSeconds float64           `json:"uptime_seconds"`
+	Runtime       RuntimeDTO        `json:"runtime"`
+	Cache         CacheStatsDTO     `json:"cache"`
+	Consumer      ConsumerStatusDTO `json:"consumer"`
+}
+
+// BuildDTO: VCS-поля пустые, если бинарь собран без информации о репозитории.
+type BuildDTO struct {
+	GoVersion  string `json:"go_version"`
+	Path       string `json:"path"`
+	Version    string `json:"version"`
+	Revision   string `json:"vcs_revision,omitempty"`
+	CommitTime string `json:"vcs_time,omitempty"`
+	Modified   bool   `json:"vcs_modified"`
+}
+
+type RuntimeDTO struct {
+	Goroutines uint64 `json:"goroutines"`
+	GOMAXPROCS uint64 `json:"gomaxprocs"`
+	HeapBytes  uint64 `json:"heap_bytes"`
+	HeapGoal   uint64 `json:"heap_goal_bytes"`
+	GCCycles   uint64 `json:"gc_cycles"`
+}
+
+type CacheStatsDTO struct {
+	Entries    int     `json:"entries"`
+	Bytes      int64   `json:"bytes"`
+	Expired    uint64  `json:"expired_total"`
+	Evicted    uint64  `json:"evicted_total"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+}
+
+// ConsumerStatusDTO: assignment — партиции топиков, назначенные этому экземпляру группы.
+type ConsumerStatusDTO struct {
+	Assignment map[string][]int32 `json:"assignment"`
+}
+
+func MapToCacheStatsDTO(stats cache.Stats) CacheStatsDTO {
+	return CacheStatsDTO{
+		Entries:    stats.Entries,
+		Bytes:      stats.Bytes,
+		Expired:    stats.Expired,
+		Evicted:    stats.Evicted,
+		TTLSeconds: stats.TTL.Seconds(),
+	}
+}
@@ -0,0 +1,263 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"web_demoservice/internal/domain"
+)
+
+// Вложенные сущности заказа, которые можно запросить через ?include= или целиком в ?fields=.
+var entityIncludes = map[string]domain.Include{
+	"delivery": domain.IncludeDelivery,
+	"payment":  domain.IncludePayment,
+	"items":    domain.IncludeItems,
+}
+
+// orderFieldPaths — допустимые пути для ?fields=, собранные по json-тегам OrderWithInformationDTO.
+var orderFieldPaths = collectFieldPaths(reflect.TypeOf(OrderWithInformationDTO{}))
+
+// Selection описывает разреженное представление заказа: ?fields=a,b.c и ?include=delivery,items.
+type Selection struct {
+	fields     []string
+	include    domain.Include
+	hasInclude bool
+}
+
+// SelectionError указывает, какой из параметров выборки некорректен.
+type SelectionError struct {
+	Param  string
+	Reason string
+}
+
+func (e *SelectionError) Error() string {
+	return e.Param + ": " + e.Reason
+}
+
+func ParseSelection(fields, include string) (Selection, error) {
+	var sel Selection
+
+	if include != "" {
+		sel.hasInclude = true
+		for _, name := range splitList(include) {
+			part, ok := entityIncludes[name]
+			if !ok {
+				return Selection{}, &SelectionError{Param: "include", Reason: fmt.Sprintf("unknown include %q", name)}
+			}
+			sel.include |= part
+		}
+	}
+
+	if fields != "" {
+		seen := make(map[string]bool)
+		for _, path := range splitList(fields) {
+			if !orderFieldPaths[path] {
+				return Selection{}, &SelectionError{Param: "fields", Reason: fmt.Sprintf("unknown field %q", path)}
+			}
+			if !seen[path] {
+				seen[path] = true
+				sel.fields = append(sel.fields, path)
+			}
+		}
+		sort.Strings(sel.fields)
+	}
+
+	return sel, nil
+}
+
+// IsFull сообщает, что параметры не заданы и нужен полный заказ.
+func (s Selection) IsFull() bool {
+	return len(s.fields) == 0 && !s.hasInclude
+}
+
+// Include — сущности, которые нужно загрузить для этого представления.
+func (s Selection) Include() domain.Include {
+	if s.IsFull() {
+		return domain.IncludeAll
+	}
+	if len(s.fields) == 0 {
+		return s.include
+	}
+
+	include := s.include
+	for _, path := range s.fields {
+		top, _, _ := strings.Cut(path, ".")
+		include |= entityIncludes[top]
+	}
+	return include
+}
+
+// Key — каноническая запись выборки, одинаковая для эквивалентных запросов.
+func (s Selection) Key() string {
+	if s.IsFull() {
+		return ""
+	}
+
+	var include []string
+	for name, part := range entityIncludes {
+		if s.include.Has(part) {
+			include = append(include, name)
+		}
+	}
+	sort.Strings(include)
+
+	return "fields=" + strings.Join(s.fields, ",") + ";include=" + strings.Join(include, ",")
+}
+
+// Returned — отданные клиенту поля заказа (для аудита): "*" для полного заказа, иначе
+// отсортированные пути, сущность целиком — её именем.
+func (s Selection) Returned() []string {
+	if s.IsFull() {
+		return []string{"*"}
+	}
+
+	var out []string
+	for name, part := range entityIncludes {
+		if s.include.Has(part) {
+			out = append(out, name)
+		}
+	}
+	if len(s.fields) == 0 {
+		for path := range orderFieldPaths {
+			if _, isEntity := entityIncludes[path]; !isEntity && !strings.Contains(path, ".") {
+				out = append(out, path)
+			}
+		}
+	}
+	for _, path := range s.fields {
+		top, _, _ := strings.Cut(path, ".")
+		if part, isEntity := entityIncludes[top]; isEntity && s.include.Has(part) {
+			continue
+		}
+		out = append(out, path)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+// Apply строит представление заказа. Для полной выборки DTO возвращается как есть.
+// Без fields отдаются все поля заказа и только включённые сущности; с fields — перечисленные
+// поля плюс включённые сущности целиком.
+func (s Selection) Apply(order OrderWithInformationDTO) (any, error) {
+	if s.IsFull() {
+		return order, nil
+	}
+
+	raw, err := json.Marshal(order)
+	if err != nil {
+		return nil, fmt.Errorf("marshal order: %w", err)
+	}
+	var full map[string]any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err = dec.Decode(&full); err != nil {
+		return nil, fmt.Errorf("decode order: %w", err)
+	}
+
+	out := make(map[string]any)
+	whole := make(map[string]bool)
+
+	for name, part := range entityIncludes {
+		if s.include.Has(part) {
+			out[name] = full[name]
+			whole[name] = true
+		}
+	}
+
+	if len(s.fields) == 0 {
+		for key, value := range full {
+			if _, isEntity := entityIncludes[key]; !isEntity {
+				out[key] = value
+			}
+		}
+		return out, nil
+	}
+
+	for _, path := range s.fields {
+		top, sub, nested := strings.Cut(path, ".")
+		if !nested {
+			out[top] = full[top]
+			whole[top] = true
+			continue
+		}
+		if whole[top] {
+			continue
+		}
+
+		switch value := full[top].(type) {
+		case map[string]any:
+			dst, _ := out[top].(map[string]any)
+			if dst == nil {
+				dst = make(map[string]any)
+				out[top] = dst
+			}
+			dst[sub] = value[sub]
+		case []any:
+			dst, _ := out[top].([]any)
+			if dst == nil {
+				dst = make([]any, len(value))
+				for i := range dst {
+					dst[i] = make(map[string]any)
+				}
+				out[top] = dst
+			}
+			for i, el := range value {
+				if m, ok := el.(map[string]any); ok {
+					dst[i].(map[string]any)[sub] = m[sub]
+				}
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func collectFieldPaths(typ reflect.Type) map[string]bool {
+	paths := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		paths[name] = true
+
+		nested := field.Type
+		if nested.Kind() == reflect.Slice {
+			nested = nested.Elem()
+		}
+		if _, isEntity := entityIncludes[name]; isEntity && nested.Kind() == reflect.Struct {
+			for j := 0; j < nested.NumField(); j++ {
+				if sub := jsonName(nested.Field(j)); sub != "" {
+					paths[name+"."+sub] = true
+				}
+			}
+		}
+	}
+	return paths
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+	"web_demoservice/internal/domain"
+)
+
+func TestParseSelection_Full(t *testing.T) {
+	sel, err := ParseSelection("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.IsFull() || sel.Include() != domain.IncludeAll || sel.Key() != "" {
+		t.Fatalf("expected full selection, got %+v", sel)
+	}
+}
+
+func TestParseSelection_IncludeFromFields(t *testing.T) {
+	sel, err := ParseSelection("order_uid,items.name,track_number", "delivery")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := domain.IncludeItems | domain.IncludeDelivery; sel.Include() != want {
+		t.Fatalf("expected include %d, got %d", want, sel.Include())
+	}
+}
+
+func TestParseSelection_Unknown(t *testing.T) {
+	if _, err := ParseSelection("payment.secret", ""); err == nil {
+		t.Fatalf("expected error for unknown field")
+	}
+	if _, err := ParseSelection("", "customer"); err == nil {
+		t.Fatalf("expected error for unknown include")
+	}
+}
+
+func TestSelection_Key_IsCanonical(t *testing.T) {
+	a, _ := ParseSelection("track_number,order_uid", "items,payment")
+	b, _ := ParseSelection("order_uid, track_number,order_uid", "payment,items")
+	if a.Key() != b.Key() {
+		t.Fatalf("expected equal keys, got %q and %q", a.Key(), b.Key())
+	}
+}
+
+func TestSelection_Returned(t *testing.T) {
+	full, _ := ParseSelection("", "")
+	if got := strings.Join(full.Returned(), ","); got != "*" {
+		t.Fatalf("expected *, got %q", got)
+	}
+
+	sel, _ := ParseSelection("track_number,delivery.email,items.name", "delivery")
+	if got, want := strings.Join(sel.Returned(), ","), "delivery,items.name,track_number"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSelection_Apply(t *testing.T) {
+	order := OrderWithInformationDTO{
+		OrderUID:    "uid",
+		TrackNumber: "TRACK",
+		Delivery:    DeliveryDTO{Name: "Name", Email: "mail@test.com"},
+		Payment:     PaymentDTO{Amount: 100},
+		Items:       []ItemDTO{{Name: "a", Price: 1}, {Name: "b", Price: 2}},
+	}
+
+	sel, _ := ParseSelection("order_uid,items.name", "payment")
+	got, err := sel.Apply(order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := got.(map[string]any)
+	if len(out) != 3 || out["order_uid"] != "uid" {
+		t.Fatalf("expected order_uid, items and payment, got %v", out)
+	}
+	items := out["items"].([]any)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	for _, item := range items {
+		if m := item.(map[string]any); len(m) != 1 || m["name"] == nil {
+			t.Fatalf("expected items with name only, got %v", m)
+		}
+	}
+	if _, ok := out["payment"].(map[string]any)["amount"]; !ok {
+		t.Fatalf("expected whole payment to be included, got %v", out["payment"])
+	}
+}
+
+func TestSelection_Apply_IncludeOnly(t *testing.T) {
+	sel, _ := ParseSelection("", "items")
+	got, err := sel.Apply(OrderWithInformationDTO{OrderUID: "uid"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := got.(map[string]any)
+	if _, ok := out["delivery"]; ok {
+		t.Fatalf("expected delivery to be omitted")
+	}
+	if _, ok := out["payment"]; ok {
+		t.Fatalf("expected payment to be omitted")
+	}
+	if out["order_uid"] != "uid" || out["track_number"] == nil {
+		t.Fatalf("expected all order fields, got %v", out)
+	}
+}
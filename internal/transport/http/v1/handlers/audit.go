@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"web_demoservice/internal/auth"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
+	"web_demoservice/internal/transport/http/v1/dto"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
+type AuditService interface {
+	QueryEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error)
+}
+
+// AuditOrderHandler записывает в журнал аудита успешные чтения заказа: кто, какой заказ
+// и какие поля получил. 304 не пишется — данные клиенту не отдаются.
+type AuditOrderHandler struct {
+	next     OrderHTTPHandler
+	recorder AuditRecorder
+}
+
+func NewAuditOrderHandler(next OrderHTTPHandler, recorder AuditRecorder) *AuditOrderHandler {
+	return &AuditOrderHandler{next: next, recorder: recorder}
+}
+
+func (h *AuditOrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	h.next.GetOrder(rec, r)
+	if rec.status != http.StatusOK {
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["order_id"])
+	if err != nil {
+		return
+	}
+	// Запрос уже разобран обработчиком, здесь выборка заведомо корректна.
+	query := r.URL.Query()
+	selection, _ := dto.ParseSelection(query.Get("fields"), query.Get("include"))
+
+	h.recorder.Record(r.Context(), domain.AuditEvent{
+		Action:  domain.AuditActionOrderRead,
+		OrderID: &id,
+		Fields:  selection.Returned(),
+		Details: map[string]any{
+			"transport":  "http",
+			"pii_masked": !auth.FromContext(r.Context()).HasScope(auth.ScopeOrdersReadPII),
+		},
+	})
+}
+
+type AuditHandler struct {
+	service  AuditService
+	recorder AuditRecorder
+}
+
+func NewAuditHandler(service AuditService, recorder AuditRecorder) *AuditHandler {
+	return &AuditHandler{service: service, recorder: recorder}
+}
+
+// ListEvents отдаёт журнал по ?order_id= и/или ?actor=, от новых к старым. Сам запрос
+// к журналу тоже попадает в аудит как действие администратора.
+func (h *AuditHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.AuditFilter{Actor: query.Get("actor")}
+
+	var fields []service.FieldError
+	if raw := query.Get("order_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			fields = append(fields, service.FieldError{Field: "order_id", Reason: "must be a valid UUID"})
+		} else {
+			filter.OrderID = &id
+		}
+	}
+	if raw := query.Get("before"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			fields = append(fields, service.FieldError{Field: "before", Reason: "must be an integer"})
+		}
+		filter.Before = n
+	}
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			fields = append(fields, service.FieldError{Field: "limit", Reason: "must be an integer"})
+		}
+		filter.Limit = n
+	}
+	if len(fields) > 0 {
+		problem.Write(w, r, service.InvalidInput(service.CodeInvalidAuditFilter, "invalid audit filter", fields...))
+		return
+	}
+
+	events, err := h.service.QueryEvents(r.Context(), filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	details := map[string]any{"transport": "http", "results": len(events)}
+	if filter.Actor != "" {
+		details["actor"] = filter.Actor
+	}
+	h.recorder.Record(r.Context(), domain.AuditEvent{
+		Action:  domain.AuditActionAuditQuery,
+		OrderID: filter.OrderID,
+		Details: details,
+	})
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = service.DefaultAuditLimit
+	}
+	writeJSON(w, http.StatusOK, dto.MapToAuditEventsDTO(events, limit))
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type mockAuditRecorder struct {
+	events []domain.AuditEvent
+}
+
+func (m *mockAuditRecorder) Record(_ context.Context, event domain.AuditEvent) {
+	m.events = append(m.events, event)
+}
+
+type mockAuditService struct {
+	events []domain.AuditEvent
+	filter domain.AuditFilter
+}
+
+func (m *mockAuditService) QueryEvents(_ context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	m.filter = filter
+	return m.events, nil
+}
+
+func TestAuditOrderHandler_RecordsRead(t *testing.T) {
+	order := sampleOrder(uuid.New())
+	recorder := &mockAuditRecorder{}
+	h := NewAuditOrderHandler(NewOrderHandler(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return &order, nil
+		},
+	}, testMasker), recorder)
+
+	id := order.ID.String()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id+"?fields=track_number&include=payment", nil)
+	req = mux.SetURLVars(req, map[string]string{"order_id": id})
+	h.GetOrder(httptest.NewRecorder(), req)
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("expected one audit event, got %d", len(recorder.events))
+	}
+	event := recorder.events[0]
+	if event.Action != domain.AuditActionOrderRead || *event.OrderID != order.ID {
+		t.Fatalf("unexpected audit event: %+v", event)
+	}
+	if got := strings.Join(event.Fields, ","); got != "payment,track_number" {
+		t.Fatalf("expected returned fields payment,track_number, got %q", got)
+	}
+	if event.Details["pii_masked"] != true {
+		t.Fatalf("expected masked read, got %v", event.Details)
+	}
+}
+
+func TestAuditOrderHandler_SkipsFailedRead(t *testing.T) {
+	recorder := &mockAuditRecorder{}
+	h := NewAuditOrderHandler(NewOrderHandler(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return nil, service.NotFound(service.CodeOrderNotFound, "order not found", nil)
+		},
+	}, testMasker), recorder)
+
+	id := uuid.NewString()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id, nil)
+	req = mux.SetURLVars(req, map[string]string{"order_id": id})
+	h.GetOrder(httptest.NewRecorder(), req)
+
+	if len(recorder.events) != 0 {
+		t.Fatalf("expected no audit events, got %+v", recorder.events)
+	}
+}
+
+func TestAuditHandler_ListEvents(t *testing.T) {
+	orderID := uuid.New()
+	svc := &mockAuditService{events: []domain.AuditEvent{
+		{ID: 7, Actor: "frontend", Action: domain.AuditActionOrderRead, OrderID: &orderID, Fields: []string{"*"}},
+		{ID: 3, Actor: "frontend", Action: domain.AuditActionOrderRead, OrderID: &orderID},
+	}}
+	recorder := &mockAuditRecorder{}
+	h := NewAuditHandler(svc, recorder)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit/events?order_id="+orderID.String()+"&limit=2", nil)
+	rec := httptest.NewRecorder()
+	h.ListEvents(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if svc.filter.OrderID == nil || *svc.filter.OrderID != orderID || svc.filter.Limit != 2 {
+		t.Fatalf("unexpected filter: %+v", svc.filter)
+	}
+
+	var got struct {
+		Events     []map[string]any `json:"events"`
+		NextBefore int64            `json:"next_before"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got.Events) != 2 || got.Events[0]["order_uid"] != orderID.String() || got.NextBefore != 3 {
+		t.Fatalf("unexpected response: %s", rec.Body.String())
+	}
+
+	if len(recorder.events) != 1 || recorder.events[0].Action != domain.AuditActionAuditQuery {
+		t.Fatalf("expected the query itself to be audited, got %+v", recorder.events)
+	}
+}
+
+func TestAuditHandler_ListEvents_BadOrderID(t *testing.T) {
+	h := NewAuditHandler(&mockAuditService{}, &mockAuditRecorder{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit/events?order_id=bad", nil)
+	rec := httptest.NewRecorder()
+	h.ListEvents(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), service.CodeInvalidAuditFilter) {
+		t.Fatalf("expected %s code, got %s", service.CodeInvalidAuditFilter, rec.Body.String())
+	}
+}
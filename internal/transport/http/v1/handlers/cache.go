@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
+	"web_demoservice/internal/transport/http/v1/dto"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	DefaultCacheListLimit = 100
+	MaxCacheListLimit     = 1000
+)
+
+type CacheAdmin interface {
+	Entries() []domain.CacheEntry
+	Entry(id uuid.UUID) (domain.CacheEntry, bool)
+	Delete(ctx context.Context, id uuid.UUID)
+	Flush(ctx context.Context) int
+}
+
+type CacheHandler struct {
+	cache    CacheAdmin
+	recorder AuditRecorder
+}
+
+func NewCacheHandler(cache CacheAdmin, recorder AuditRecorder) *CacheHandler {
+	return &CacheHandler{cache: cache, recorder: recorder}
+}
+
+// ListEntries отдаёт метаданные записей по возрастанию order_uid, страницами по ?limit=
+// после ?after=.
+func (h *CacheHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := DefaultCacheListLimit
+
+	var (
+		fields []service.FieldError
+		after  string
+	)
+	if raw := query.Get("after"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			fields = append(fields, service.FieldError{Field: "after", Reason: "must be a valid UUID"})
+		}
+		after = id.String()
+	}
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxCacheListLimit {
+			fields = append(fields, service.FieldError{Field: "limit", Reason: "must be an integer between 1 and " + strconv.Itoa(MaxCacheListLimit)})
+		}
+		limit = n
+	}
+	if len(fields) > 0 {
+		problem.Write(w, r, service.InvalidInput(service.CodeInvalidCacheQuery, "invalid cache query", fields...))
+		return
+	}
+
+	entries := h.cache.Entries()
+	start := 0
+	if after != "" {
+		start = sort.Search(len(entries), func(i int) bool { return entries[i].OrderID.String() > after })
+	}
+	end := min(start+limit, len(entries))
+
+	writeJSON(w, http.StatusOK, dto.MapToCacheEntriesDTO(entries[start:end], len(entries), limit, time.Now()))
+}
+
+func (h *CacheHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.entryID(w, r)
+	if !ok {
+		return
+	}
+	entry, found := h.cache.Entry(id)
+	if !found {
+		problem.Write(w, r, service.NotFound(service.CodeCacheEntryNotFound, "order is not cached", nil))
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.MapToCacheEntryDTO(entry, time.Now()))
+}
+
+// EvictEntry убирает заказ из кэша; следующее чтение пойдёт в БД.
+func (h *CacheHandler) EvictEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.entryID(w, r)
+	if !ok {
+		return
+	}
+	if _, found := h.cache.Entry(id); !found {
+		problem.Write(w, r, service.NotFound(service.CodeCacheEntryNotFound, "order is not cached", nil))
+		return
+	}
+
+	h.cache.Delete(r.Context(), id)
+	h.recorder.Record(r.Context(), domain.AuditEvent{
+		Action:  domain.AuditActionCacheEvict,
+		OrderID: &id,
+		Details: map[string]any{"transport": "http"},
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Flush очищает кэш целиком.
+func (h *CacheHandler) Flush(w http.ResponseWriter, r *http.Request) {
+	evicted := h.cache.Flush(r.Context())
+	h.recorder.Record(r.Context(), domain.AuditEvent{
+		Action:  domain.AuditActionCacheFlush,
+		Details: map[string]any{"transport": "http", "evicted": evicted},
+	})
+	writeJSON(w, http.StatusOK, dto.CacheFlushDTO{Evicted: evicted})
+}
+
+func (h *CacheHandler) entryID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["order_id"])
+	if err != nil {
+		problem.Write(w, r, service.InvalidInput(service.CodeInvalidOrderID, "invalid order_id",
+			service.FieldError{Field: "order_id", Reason: "must be a valid UUID"}))
+		return uuid.Nil, false
+	}
+	return id, true
+}
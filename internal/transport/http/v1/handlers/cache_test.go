@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"web_demoservice/internal/cache"
+	"web_demoservice/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func newTestCache(t *testing.T, n int) (*cache.Cache, []domain.CacheEntry) {
+	t.Helper()
+	c := cache.NewCache(time.Minute)
+	for i := 0; i < n; i++ {
+		id := uuid.New()
+		c.Set(context.Background(), id, sampleOrder(id))
+	}
+	return c, c.Entries()
+}
+
+func TestCacheHandler_ListEntries(t *testing.T) {
+	c, entries := newTestCache(t, 3)
+	h := NewCacheHandler(c, &mockAuditRecorder{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/entries?limit=2&after="+entries[0].OrderID.String(), nil)
+	rec := httptest.NewRecorder()
+	h.ListEntries(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	var got struct {
+		Entries []struct {
+			OrderUID string `json:"order_uid"`
+		} `json:"entries"`
+		Total     int    `json:"total"`
+		NextAfter string `json:"next_after"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.Total != 3 || len(got.Entries) != 2 || got.Entries[0].OrderUID != entries[1].OrderID.String() ||
+		got.NextAfter != entries[2].OrderID.String() {
+		t.Fatalf("unexpected page: %s", rec.Body.String())
+	}
+}
+
+func TestCacheHandler_ListEntries_InvalidLimit(t *testing.T) {
+	c, _ := newTestCache(t, 0)
+	h := NewCacheHandler(c, &mockAuditRecorder{})
+
+	rec := httptest.NewRecorder()
+	h.ListEntries(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/entries?limit=0", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestCacheHandler_EvictEntry(t *testing.T) {
+	c, entries := newTestCache(t, 1)
+	recorder := &mockAuditRecorder{}
+	h := NewCacheHandler(c, recorder)
+	id := entries[0].OrderID
+
+	evict := func() int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/cache/entries/"+id.String(), nil)
+		req = mux.SetURLVars(req, map[string]string{"order_id": id.String()})
+		rec := httptest.NewRecorder()
+		h.EvictEntry(rec, req)
+		return rec.Code
+	}
+
+	if code := evict(); code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d", http.StatusNoContent, code)
+	}
+	if _, ok := c.Entry(id); ok {
+		t.Fatalf("expected entry to be evicted")
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Action != domain.AuditActionCacheEvict || *recorder.events[0].OrderID != id {
+		t.Fatalf("expected eviction to be audited, got %+v", recorder.events)
+	}
+	if code := evict(); code != http.StatusNotFound {
+		t.Fatalf("expected %d for missing entry, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestCacheHandler_Flush(t *testing.T) {
+	c, _ := newTestCache(t, 2)
+	recorder := &mockAuditRecorder{}
+	h := NewCacheHandler(c, recorder)
+
+	rec := httptest.NewRecorder()
+	h.Flush(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/cache/entries", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "{\"evicted\":2}\n" {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	if c.Stats().Entries != 0 || len(recorder.events) != 1 || recorder.events[0].Action != domain.AuditActionCacheFlush {
+		t.Fatalf("expected flushed and audited cache")
+	}
+}
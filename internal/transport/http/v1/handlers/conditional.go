@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+	"web_demoservice/internal/domain"
+)
+
+// Заказ может содержать персональные данные, поэтому кэшировать его можно только на клиенте
+// и только с ревалидацией по ETag.
+const orderCacheControl = "private, no-cache"
+
+// Представление зависит от скоупов клиента (маскирование PII).
+const varyHeader = "Authorization, X-API-Key"
+
+const maskedVariant = "|pii=masked"
+
+func setVersionHeaders(w http.ResponseWriter, version *domain.OrderVersion) {
+	w.Header().Set("ETag", version.ETag)
+	w.Header().Set("Last-Modified", version.LastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", orderCacheControl)
+}
+
+// variantVersion делает ETag уникальным для каждого представления заказа:
+// разреженного (?fields=, ?include=) и/или с замаскированными PII.
+func variantVersion(version *domain.OrderVersion, variant string) *domain.OrderVersion {
+	if variant == "" {
+		return version
+	}
+
+	sum := sha256.Sum256([]byte(variant))
+	return &domain.OrderVersion{
+		ETag:         strings.TrimSuffix(version.ETag, `"`) + "-" + hex.EncodeToString(sum[:4]) + `"`,
+		LastModified: version.LastModified,
+	}
+}
+
+func hasConditionalHeaders(r *http.Request) bool {
+	return r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != ""
+}
+
+// notModified проверяет условия по RFC 9110: If-None-Match приоритетнее If-Modified-Since.
+func notModified(r *http.Request, version *domain.OrderVersion) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, version.ETag)
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !version.LastModified.After(since.Truncate(time.Second))
+	}
+
+	return false
+}
+
+// etagMatches выполняет weak-сравнение со списком из If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
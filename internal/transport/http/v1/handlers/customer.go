@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/transport/http/problem"
+	"web_demoservice/internal/transport/http/v1/dto"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type CustomerService interface {
+	ExportCustomer(ctx context.Context, customerID string) (*domain.CustomerExport, error)
+	EraseCustomer(ctx context.Context, customerID string) (*domain.CustomerErasure, error)
+}
+
+type CustomerHandler struct {
+	service  CustomerService
+	recorder AuditRecorder
+}
+
+func NewCustomerHandler(service CustomerService, recorder AuditRecorder) *CustomerHandler {
+	return &CustomerHandler{service: service, recorder: recorder}
+}
+
+// ExportCustomer отдаёт все заказы клиента одним JSON-файлом (запрос доступа к данным).
+func (h *CustomerHandler) ExportCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID := mux.Vars(r)["customer_id"]
+	export, err := h.service.ExportCustomer(r.Context(), customerID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(export.Orders))
+	for _, order := range export.Orders {
+		ids = append(ids, order.ID)
+	}
+	h.record(r, domain.AuditActionCustomerExport, customerID, ids)
+
+	w.Header().Set("Content-Disposition", `attachment; filename="customer-export.json"`)
+	writeJSON(w, http.StatusOK, dto.MapToCustomerExportDTO(export))
+}
+
+// EraseCustomer анонимизирует PII доставки всех заказов клиента (право на удаление).
+func (h *CustomerHandler) EraseCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID := mux.Vars(r)["customer_id"]
+	erasure, err := h.service.EraseCustomer(r.Context(), customerID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	h.record(r, domain.AuditActionCustomerErase, customerID, erasure.OrderIDs)
+	writeJSON(w, http.StatusOK, dto.MapToCustomerErasureDTO(erasure))
+}
+
+func (h *CustomerHandler) record(r *http.Request, action, customerID string, orderIDs []uuid.UUID) {
+	for i := range orderIDs {
+		h.recorder.Record(r.Context(), domain.AuditEvent{
+			Action:  action,
+			OrderID: &orderIDs[i],
+			Details: map[string]any{"transport": "http", "customer_id": customerID},
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("failed to encode response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type mockCustomerService struct {
+	export  *domain.CustomerExport
+	erasure *domain.CustomerErasure
+	err     error
+}
+
+func (m *mockCustomerService) ExportCustomer(context.Context, string) (*domain.CustomerExport, error) {
+	return m.export, m.err
+}
+
+func (m *mockCustomerService) EraseCustomer(context.Context, string) (*domain.CustomerErasure, error) {
+	return m.erasure, m.err
+}
+
+func TestCustomerHandler_ExportCustomer(t *testing.T) {
+	order := sampleOrder(uuid.New())
+	recorder := &mockAuditRecorder{}
+	h := NewCustomerHandler(&mockCustomerService{export: &domain.CustomerExport{
+		CustomerID: "customer",
+		Orders:     []domain.OrderWithInformation{order},
+	}}, recorder)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/customers/customer/export", nil)
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "customer"})
+	rec := httptest.NewRecorder()
+	h.ExportCustomer(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	var got struct {
+		Orders []map[string]any `json:"orders"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	// Выгрузка идёт самому клиенту: доставка не маскируется.
+	delivery, _ := got.Orders[0]["delivery"].(map[string]any)
+	if len(got.Orders) != 1 || delivery["email"] != order.Delivery.Email {
+		t.Fatalf("unexpected export: %s", rec.Body.String())
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Action != domain.AuditActionCustomerExport ||
+		*recorder.events[0].OrderID != order.ID || recorder.events[0].Details["customer_id"] != "customer" {
+		t.Fatalf("expected export to be audited per order, got %+v", recorder.events)
+	}
+}
+
+func TestCustomerHandler_EraseCustomer(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	recorder := &mockAuditRecorder{}
+	h := NewCustomerHandler(&mockCustomerService{erasure: &domain.CustomerErasure{
+		CustomerID: "customer", OrderIDs: ids, Anonymized: 1,
+	}}, recorder)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/customers/customer/erase", nil)
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "customer"})
+	rec := httptest.NewRecorder()
+	h.EraseCustomer(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	if len(recorder.events) != 2 || recorder.events[1].Action != domain.AuditActionCustomerErase || *recorder.events[1].OrderID != ids[1] {
+		t.Fatalf("expected erasure to be audited per order, got %+v", recorder.events)
+	}
+}
+
+func TestCustomerHandler_UnknownCustomer(t *testing.T) {
+	recorder := &mockAuditRecorder{}
+	h := NewCustomerHandler(&mockCustomerService{
+		err: service.NotFound(service.CodeCustomerNotFound, "customer has no orders", nil),
+	}, recorder)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/customers/nobody/erase", nil)
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "nobody"})
+	rec := httptest.NewRecorder()
+	h.EraseCustomer(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	if len(recorder.events) != 0 {
+		t.Fatalf("expected no audit events, got %+v", recorder.events)
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+	"web_demoservice/internal/cache"
+	"web_demoservice/internal/transport/http/v1/dto"
+)
+
+type CacheStatsSource interface {
+	Stats() cache.Stats
+}
+
+type AssignmentSource interface {
+	Assignment() map[string][]int32
+}
+
+// Метрики runtime/metrics для /debug/status; полный набор отдаётся в /metrics.
+var runtimeSamples = []string{
+	"/sched/goroutines:goroutines",
+	"/sched/gomaxprocs:threads",
+	"/memory/classes/heap/objects:bytes",
+	"/gc/heap/goal:bytes",
+	"/gc/cycles/total:gc-cycles",
+}
+
+type DebugHandler struct {
+	cache      CacheStatsSource
+	consumer   AssignmentSource
+	configHash func() string
+	startedAt  time.Time
+}
+
+// NewDebugHandler: configHash вызывается на каждый запрос, чтобы страница показывала
+// конфиг после hot reload.
+func NewDebugHandler(cache CacheStatsSource, consumer AssignmentSource, configHash func() string, startedAt time.Time) *DebugHandler {
+	return &DebugHandler{cache: cache, consumer: consumer, configHash: configHash, startedAt: startedAt}
+}
+
+// Status отдаёт сборку, хэш конфига, аптайм, состояние runtime, кэша и консьюмера.
+func (h *DebugHandler) Status(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	assignment := h.consumer.Assignment()
+	if assignment == nil {
+		assignment = map[string][]int32{}
+	}
+	writeJSON(w, http.StatusOK, dto.StatusDTO{
+		Build:         buildInfo(),
+		ConfigHash:    h.configHash(),
+		StartedAt:     h.startedAt,
+		UptimeSeconds: now.Sub(h.startedAt).Seconds(),
+		Runtime:       runtimeStats(),
+		Cache:         dto.MapToCacheStatsDTO(h.cache.Stats()),
+		Consumer:      dto.ConsumerStatusDTO{Assignment: assignment},
+	})
+}
+
+func buildInfo() dto.BuildDTO {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return dto.BuildDTO{}
+	}
+	build := dto.BuildDTO{
+		GoVersion: info.GoVersion,
+		Path:      info.Main.Path,
+		Version:   info.Main.Version,
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			build.Revision = s.Value
+		case "vcs.time":
+			build.CommitTime = s.Value
+		case "vcs.modified":
+			build.Modified = s.Value == "true"
+		}
+	}
+	return build
+}
+
+func runtimeStats() dto.RuntimeDTO {
+	samples := make([]metrics.Sample, len(runtimeSamples))
+	for i, name := range runtimeSamples {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	values := make(map[string]uint64, len(samples))
+	for _, s := range samples {
+		if s.Value.Kind() == metrics.KindUint64 {
+			values[s.Name] = s.Value.Uint64()
+		}
+	}
+	return dto.RuntimeDTO{
+		Goroutines: values["/sched/goroutines:goroutines"],
+		GOMAXPROCS: values["/sched/gomaxprocs:threads"],
+		HeapBytes:  values["/memory/classes/heap/objects:bytes"],
+		HeapGoal:   values["/gc/heap/goal:bytes"],
+		GCCycles:   values["/gc/cycles/total:gc-cycles"],
+	}
+}
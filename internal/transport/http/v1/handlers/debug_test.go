@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"web_demoservice/internal/transport/http/v1/dto"
+)
+
+type stubAssignment map[string][]int32
+
+func (s stubAssignment) Assignment() map[string][]int32 { return s }
+
+func TestDebugHandler_Status(t *testing.T) {
+	c, _ := newTestCache(t, 2)
+	startedAt := time.Now().Add(-time.Minute)
+	h := NewDebugHandler(c, stubAssignment{"orders": {0, 2}}, func() string { return "sha256:abc" }, startedAt)
+
+	rec := httptest.NewRecorder()
+	h.Status(rec, httptest.NewRequest(http.MethodGet, "/debug/status", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+	var got dto.StatusDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.ConfigHash != "sha256:abc" || got.UptimeSeconds < 60 || got.Build.GoVersion == "" {
+		t.Fatalf("unexpected status: %s", rec.Body.String())
+	}
+	if got.Cache.Entries != 2 || got.Cache.TTLSeconds != 60 || len(got.Consumer.Assignment["orders"]) != 2 {
+		t.Fatalf("expected cache stats and assignment, got %s", rec.Body.String())
+	}
+	if got.Runtime.Goroutines == 0 || got.Runtime.HeapBytes == 0 {
+		t.Fatalf("expected runtime metrics, got %+v", got.Runtime)
+	}
+}
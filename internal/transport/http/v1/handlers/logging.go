@@ -1,11 +1,14 @@
 package handlers
 
 import (
-	"log/slog"
 	"net/http"
-	"time"
+	"web_demoservice/internal/logging"
+
+	"github.com/gorilla/mux"
 )
 
+// LoggingOrderHandler добавляет order_id в контекст запроса. Строку access log и
+// request_id ставит middleware на уровне роутера.
 type LoggingOrderHandler struct {
 	next OrderHTTPHandler
 }
@@ -15,18 +18,11 @@ func NewLoggingOrderHandler(next OrderHTTPHandler) *LoggingOrderHandler {
 }
 
 func (h *LoggingOrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
-	start := time.Now()
-	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
-
-	h.next.GetOrder(rec, r)
-
-	slog.Info(
-		"http request",
-		slog.String("method", r.Method),
-		slog.String("path", r.URL.Path),
-		slog.Int("status", rec.status),
-		slog.Duration("duration", time.Since(start)),
-	)
+	// order_id попадает во все записи ниже по стеку (сервис, репозиторий, кэш).
+	if orderID := mux.Vars(r)["order_id"]; orderID != "" {
+		r = r.WithContext(logging.WithOrderID(r.Context(), orderID))
+	}
+	h.next.GetOrder(w, r)
 }
 
 type statusRecorder struct {
@@ -4,17 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
+	"web_demoservice/internal/auth"
 	"web_demoservice/internal/domain"
+	"web_demoservice/internal/pii"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
 	"web_demoservice/internal/transport/http/v1/dto"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
-	"github.com/jackc/pgx/v5"
 )
 
 type OrderService interface {
-	GetOrder(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	GetOrderParts(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error)
+	OrderVersion(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool)
 }
 
 type OrderHTTPHandler interface {
@@ -23,42 +28,96 @@ type OrderHTTPHandler interface {
 
 type OrderHandler struct {
 	service OrderService
+	masker  *pii.Masker
 }
 
-func NewOrderHandler(service OrderService) *OrderHandler {
+func NewOrderHandler(service OrderService, masker *pii.Masker) *OrderHandler {
 	return &OrderHandler{
 		service: service,
+		masker:  masker,
 	}
 }
 
 func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	orderID := mux.Vars(r)["order_id"]
 	if orderID == "" {
-		http.Error(w, "order_id is required", http.StatusBadRequest)
+		problem.Write(w, r, service.InvalidInput(service.CodeInvalidOrderID, "order_id is required",
+			service.FieldError{Field: "order_id", Reason: "required"}))
 		return
 	}
 
 	uuid, err := uuid.Parse(orderID)
 	if err != nil {
-		http.Error(w, "invalid order_id", http.StatusBadRequest)
+		problem.Write(w, r, service.InvalidInput(service.CodeInvalidOrderID, "invalid order_id",
+			service.FieldError{Field: "order_id", Reason: "must be a valid UUID"}))
 		return
 	}
 
-	order, err := h.service.GetOrder(r.Context(), uuid)
+	query := r.URL.Query()
+	selection, err := dto.ParseSelection(query.Get("fields"), query.Get("include"))
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			http.Error(w, "order not found", http.StatusNotFound)
-			return
+		var selErr *dto.SelectionError
+		field := service.FieldError{Field: "fields", Reason: err.Error()}
+		if errors.As(err, &selErr) {
+			field = service.FieldError{Field: selErr.Param, Reason: selErr.Reason}
 		}
+		problem.Write(w, r, service.InvalidInput(service.CodeInvalidFieldSelection, "invalid field selection", field))
+		return
+	}
+
+	// Без скоупа PII клиент получает замаскированную доставку — это отдельное представление.
+	masked := !auth.FromContext(r.Context()).HasScope(auth.ScopeOrdersReadPII)
+	variant := selection.Key()
+	if masked {
+		variant += maskedVariant
+	}
+	w.Header().Set("Vary", varyHeader)
+
+	// Условный запрос к закэшированному заказу отвечаем 304 без чтения и сериализации заказа.
+	if hasConditionalHeaders(r) {
+		if version, ok := h.service.OrderVersion(r.Context(), uuid); ok {
+			version = variantVersion(version, variant)
+			if notModified(r, version) {
+				setVersionHeaders(w, version)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
+	order, err := h.service.GetOrderParts(r.Context(), uuid, selection.Include())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
 
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+	// Неполный заказ из БД даёт другой базовый ETag, чем полный из кэша: клиент получит
+	// лишний 200, но устаревший 304 невозможен.
+	version, ok := h.service.OrderVersion(r.Context(), uuid)
+	if !ok {
+		v := domain.NewOrderVersion(*order)
+		version = &v
+	}
+	version = variantVersion(version, variant)
+	setVersionHeaders(w, version)
+	if notModified(r, version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if masked {
+		order = h.masker.Order(order)
+	}
+
+	body, err := selection.Apply(dto.MapToOrderDTO(order))
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	orderDTO := dto.MapToOrderDTO(order)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err = json.NewEncoder(w).Encode(orderDTO); err != nil {
-		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	if err = json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("failed to encode response", slog.Any("error", err))
 	}
 }
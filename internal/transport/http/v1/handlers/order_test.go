@@ -6,30 +6,44 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+	"web_demoservice/internal/auth"
 	"web_demoservice/internal/domain"
+	"web_demoservice/internal/pii"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
-	"github.com/jackc/pgx/v5"
 )
 
 type mockOrderService struct {
-	getOrderFn func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	getOrderFn     func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error)
+	orderVersionFn func(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool)
+	lastInclude    domain.Include
 }
 
-func (m *mockOrderService) GetOrder(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+func (m *mockOrderService) GetOrderParts(ctx context.Context, id uuid.UUID, include domain.Include) (*domain.OrderWithInformation, error) {
+	m.lastInclude = include
 	return m.getOrderFn(ctx, id)
 }
 
+func (m *mockOrderService) OrderVersion(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool) {
+	if m.orderVersionFn != nil {
+		return m.orderVersionFn(ctx, id)
+	}
+	return nil, false
+}
+
 func TestOrderHandler_GetOrder_BadRequestOnMissingID(t *testing.T) {
 	h := NewOrderHandler(&mockOrderService{
 		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
 			t.Fatalf("service should not be called")
 			return nil, nil
 		},
-	})
+	}, testMasker)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/", nil)
 	req = mux.SetURLVars(req, map[string]string{})
@@ -48,7 +62,7 @@ func TestOrderHandler_GetOrder_BadRequestOnInvalidUUID(t *testing.T) {
 			t.Fatalf("service should not be called")
 			return nil, nil
 		},
-	})
+	}, testMasker)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/bad", nil)
 	req = mux.SetURLVars(req, map[string]string{"order_id": "bad"})
@@ -64,9 +78,9 @@ func TestOrderHandler_GetOrder_BadRequestOnInvalidUUID(t *testing.T) {
 func TestOrderHandler_GetOrder_NotFound(t *testing.T) {
 	h := NewOrderHandler(&mockOrderService{
 		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
-			return nil, pgx.ErrNoRows
+			return nil, service.NotFound(service.CodeOrderNotFound, "order not found", nil)
 		},
-	})
+	}, testMasker)
 
 	id := uuid.New().String()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id, nil)
@@ -78,6 +92,17 @@ func TestOrderHandler_GetOrder_NotFound(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
 	}
+
+	var got problem.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if got.Code != service.CodeOrderNotFound || got.Status != http.StatusNotFound {
+		t.Fatalf("unexpected problem: %+v", got)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Fatalf("expected content type %s, got %s", problem.ContentType, ct)
+	}
 }
 
 func TestOrderHandler_GetOrder_InternalError(t *testing.T) {
@@ -85,7 +110,7 @@ func TestOrderHandler_GetOrder_InternalError(t *testing.T) {
 		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
 			return nil, errors.New("boom")
 		},
-	})
+	}, testMasker)
 
 	id := uuid.New().String()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id, nil)
@@ -97,6 +122,9 @@ func TestOrderHandler_GetOrder_InternalError(t *testing.T) {
 	if rec.Code != http.StatusInternalServerError {
 		t.Fatalf("expected %d, got %d", http.StatusInternalServerError, rec.Code)
 	}
+	if strings.Contains(rec.Body.String(), "boom") {
+		t.Fatalf("internal error details leaked to client: %s", rec.Body.String())
+	}
 }
 
 func TestOrderHandler_GetOrder_OK(t *testing.T) {
@@ -105,7 +133,7 @@ func TestOrderHandler_GetOrder_OK(t *testing.T) {
 		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
 			return &order, nil
 		},
-	})
+	}, testMasker)
 
 	id := order.ID.String()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id, nil)
@@ -127,6 +155,216 @@ func TestOrderHandler_GetOrder_OK(t *testing.T) {
 	}
 }
 
+func TestOrderHandler_GetOrder_SetsValidators(t *testing.T) {
+	order := sampleOrder(uuid.New())
+	h := NewOrderHandler(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return &order, nil
+		},
+	}, testMasker)
+
+	id := order.ID.String()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id, nil)
+	req = mux.SetURLVars(req, map[string]string{"order_id": id})
+	req = withPIIScope(req)
+	rec := httptest.NewRecorder()
+
+	h.GetOrder(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got, want := rec.Header().Get("ETag"), domain.NewOrderVersion(order).ETag; got != want {
+		t.Fatalf("expected ETag %s, got %s", want, got)
+	}
+	if rec.Header().Get("Last-Modified") == "" || rec.Header().Get("Cache-Control") == "" {
+		t.Fatalf("expected Last-Modified and Cache-Control headers")
+	}
+}
+
+func TestOrderHandler_GetOrder_NotModifiedFromCache(t *testing.T) {
+	order := sampleOrder(uuid.New())
+	version := domain.NewOrderVersion(order)
+	h := NewOrderHandler(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			t.Fatalf("order should not be loaded for a matching ETag")
+			return nil, nil
+		},
+		orderVersionFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool) {
+			return &version, true
+		},
+	}, testMasker)
+
+	id := order.ID.String()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id, nil)
+	req = mux.SetURLVars(req, map[string]string{"order_id": id})
+	req = withPIIScope(req)
+	req.Header.Set("If-None-Match", `"other", `+version.ETag)
+	rec := httptest.NewRecorder()
+
+	h.GetOrder(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected %d, got %d", http.StatusNotModified, rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected empty body, got %q", rec.Body.String())
+	}
+	if rec.Header().Get("ETag") != version.ETag {
+		t.Fatalf("expected ETag %s, got %s", version.ETag, rec.Header().Get("ETag"))
+	}
+}
+
+func TestOrderHandler_GetOrder_IfModifiedSince(t *testing.T) {
+	order := sampleOrder(uuid.New())
+	version := domain.NewOrderVersion(order)
+	h := NewOrderHandler(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return &order, nil
+		},
+		orderVersionFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderVersion, bool) {
+			return &version, true
+		},
+	}, testMasker)
+
+	cases := []struct {
+		since  time.Time
+		status int
+	}{
+		{version.LastModified, http.StatusNotModified},
+		{version.LastModified.Add(-time.Hour), http.StatusOK},
+	}
+	for _, tc := range cases {
+		id := order.ID.String()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id, nil)
+		req = mux.SetURLVars(req, map[string]string{"order_id": id})
+		req.Header.Set("If-Modified-Since", tc.since.UTC().Format(http.TimeFormat))
+		rec := httptest.NewRecorder()
+
+		h.GetOrder(rec, req)
+
+		if rec.Code != tc.status {
+			t.Fatalf("since %s: expected %d, got %d", tc.since, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestOrderHandler_GetOrder_SparseFields(t *testing.T) {
+	order := sampleOrder(uuid.New())
+	svc := &mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return &order, nil
+		},
+	}
+	h := NewOrderHandler(svc, testMasker)
+
+	id := order.ID.String()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id+"?fields=order_uid,payment.amount", nil)
+	req = mux.SetURLVars(req, map[string]string{"order_id": id})
+	rec := httptest.NewRecorder()
+
+	h.GetOrder(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.lastInclude != domain.IncludePayment {
+		t.Fatalf("expected only payment to be loaded, got include %d", svc.lastInclude)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got) != 2 || got["order_uid"] != id {
+		t.Fatalf("expected order_uid and payment only, got %v", got)
+	}
+	payment, _ := got["payment"].(map[string]any)
+	if len(payment) != 1 || payment["amount"] != order.Payment.Amount {
+		t.Fatalf("expected payment.amount only, got %v", got["payment"])
+	}
+	if rec.Header().Get("ETag") == domain.NewOrderVersion(order).ETag {
+		t.Fatalf("expected sparse representation to have its own ETag")
+	}
+}
+
+func TestOrderHandler_GetOrder_UnknownField(t *testing.T) {
+	h := NewOrderHandler(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			t.Fatalf("service should not be called")
+			return nil, nil
+		},
+	}, testMasker)
+
+	id := uuid.New().String()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id+"?include=secrets", nil)
+	req = mux.SetURLVars(req, map[string]string{"order_id": id})
+	rec := httptest.NewRecorder()
+
+	h.GetOrder(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	var got problem.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if got.Code != service.CodeInvalidFieldSelection || len(got.Errors) != 1 || got.Errors[0].Field != "include" {
+		t.Fatalf("unexpected problem: %+v", got)
+	}
+}
+
+func TestOrderHandler_GetOrder_MasksPIIWithoutScope(t *testing.T) {
+	order := sampleOrder(uuid.New())
+	h := NewOrderHandler(&mockOrderService{
+		getOrderFn: func(ctx context.Context, id uuid.UUID) (*domain.OrderWithInformation, error) {
+			return &order, nil
+		},
+	}, testMasker)
+
+	get := func(req *http.Request) (*httptest.ResponseRecorder, map[string]any) {
+		rec := httptest.NewRecorder()
+		h.GetOrder(rec, mux.SetURLVars(req, map[string]string{"order_id": order.ID.String()}))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+		}
+		var got struct {
+			Delivery map[string]any `json:"delivery"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return rec, got.Delivery
+	}
+
+	reader := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "reader", Scopes: []string{auth.ScopeOrdersRead}})
+	maskedRec, masked := get(httptest.NewRequest(http.MethodGet, "/api/v1/order/"+order.ID.String(), nil).WithContext(reader))
+	if masked["phone"] != "+1***00" || masked["email"] != "m***@test.com" || masked["address"] != "***" {
+		t.Fatalf("expected masked delivery, got %v", masked)
+	}
+	if order.Delivery.Phone != "+1000" {
+		t.Fatalf("masking must not modify the source order")
+	}
+
+	clearRec, clear := get(withPIIScope(httptest.NewRequest(http.MethodGet, "/api/v1/order/"+order.ID.String(), nil)))
+	if clear["phone"] != order.Delivery.Phone || clear["email"] != order.Delivery.Email {
+		t.Fatalf("expected clear delivery for PII scope, got %v", clear)
+	}
+
+	if maskedRec.Header().Get("ETag") == clearRec.Header().Get("ETag") {
+		t.Fatalf("masked and clear representations must have different ETags")
+	}
+	if maskedRec.Header().Get("Vary") == "" {
+		t.Fatalf("expected Vary header")
+	}
+}
+
+func withPIIScope(req *http.Request) *http.Request {
+	identity := &auth.Identity{Subject: "support", Scopes: []string{auth.ScopeOrdersRead, auth.ScopeOrdersReadPII}}
+	return req.WithContext(auth.WithIdentity(req.Context(), identity))
+}
+
 func sampleOrder(id uuid.UUID) domain.OrderWithInformation {
 	internalSignature := "sig"
 	deliveryService := "delivery"
@@ -193,3 +431,11 @@ func sampleOrder(id uuid.UUID) domain.OrderWithInformation {
 		},
 	}
 }
+
+var testMasker = func() *pii.Masker {
+	m, err := pii.NewMasker(nil)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}()
@@ -0,0 +1,170 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"web_demoservice/internal/transport/http/problem"
+	"web_demoservice/internal/transport/http/v1/dto"
+)
+
+type schemaDoc struct {
+	Ref        string                `json:"$ref"`
+	Type       string                `json:"type"`
+	Format     string                `json:"format"`
+	Required   []string              `json:"required"`
+	Properties map[string]*schemaDoc `json:"properties"`
+	Items      *schemaDoc            `json:"items"`
+	AnyOf      []*schemaDoc          `json:"anyOf"`
+}
+
+// TestContract_DTOMatchesSpec падает, если структуры dto разошлись со схемами в openapi.json.
+func TestContract_DTOMatchesSpec(t *testing.T) {
+	var doc struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]*schemaDoc `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(Spec(), &doc); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+
+	var getOrder struct {
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema *schemaDoc `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+	}
+	if err := json.Unmarshal(doc.Paths["/api/v1/order/{order_id}"]["get"], &getOrder); err != nil {
+		t.Fatalf("decode getOrder: %v", err)
+	}
+	root := getOrder.Responses["200"].Content["application/json"].Schema
+	if root == nil || len(root.AnyOf) != 2 {
+		t.Fatalf("getOrder 200 application/json schema must be anyOf [Order, OrderSparse]")
+	}
+
+	c := contractChecker{t: t, schemas: doc.Components.Schemas}
+	c.check("OrderWithInformationDTO", reflect.TypeOf(dto.OrderWithInformationDTO{}), root.AnyOf[0])
+
+	// Разреженный ответ (?fields=, ?include=) содержит те же поля, но ни одно не обязательно.
+	sparse := contractChecker{t: t, schemas: doc.Components.Schemas, sparse: true}
+	sparse.check("OrderWithInformationDTO(sparse)", reflect.TypeOf(dto.OrderWithInformationDTO{}), root.AnyOf[1])
+	c.check("Problem", reflect.TypeOf(problem.Problem{}), &schemaDoc{Ref: "#/components/schemas/Problem"})
+	c.check("AuditEventsDTO", reflect.TypeOf(dto.AuditEventsDTO{}), &schemaDoc{Ref: "#/components/schemas/AuditEvents"})
+	c.check("CustomerExportDTO", reflect.TypeOf(dto.CustomerExportDTO{}), &schemaDoc{Ref: "#/components/schemas/CustomerExport"})
+	c.check("CustomerErasureDTO", reflect.TypeOf(dto.CustomerErasureDTO{}), &schemaDoc{Ref: "#/components/schemas/CustomerErasure"})
+	c.check("CacheEntriesDTO", reflect.TypeOf(dto.CacheEntriesDTO{}), &schemaDoc{Ref: "#/components/schemas/CacheEntries"})
+	c.check("CacheEntryDTO", reflect.TypeOf(dto.CacheEntryDTO{}), &schemaDoc{Ref: "#/components/schemas/CacheEntry"})
+	c.check("CacheFlushDTO", reflect.TypeOf(dto.CacheFlushDTO{}), &schemaDoc{Ref: "#/components/schemas/CacheFlush"})
+}
+
+type contractChecker struct {
+	t       *testing.T
+	schemas map[string]*schemaDoc
+	sparse  bool
+}
+
+func (c contractChecker) resolve(s *schemaDoc) *schemaDoc {
+	if s.Ref == "" {
+		return s
+	}
+	name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	resolved, ok := c.schemas[name]
+	if !ok {
+		c.t.Fatalf("unresolved $ref %s", s.Ref)
+	}
+	return resolved
+}
+
+func (c contractChecker) check(path string, typ reflect.Type, s *schemaDoc) {
+	s = c.resolve(s)
+
+	if typ == reflect.TypeOf(time.Time{}) {
+		if s.Type != "string" || s.Format != "date-time" {
+			c.t.Errorf("%s: expected string/date-time, spec has %s/%s", path, s.Type, s.Format)
+		}
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		c.expectType(path, s, "string")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		c.expectType(path, s, "integer")
+	case reflect.Float32, reflect.Float64:
+		c.expectType(path, s, "number")
+	case reflect.Bool:
+		c.expectType(path, s, "boolean")
+	case reflect.Slice:
+		c.expectType(path, s, "array")
+		if s.Items == nil {
+			c.t.Errorf("%s: spec array has no items", path)
+			return
+		}
+		c.check(path+"[]", typ.Elem(), s.Items)
+	case reflect.Struct:
+		c.expectType(path, s, "object")
+		c.checkStruct(path, typ, s)
+	case reflect.Map:
+		c.expectType(path, s, "object")
+	default:
+		c.t.Errorf("%s: unsupported go kind %s", path, typ.Kind())
+	}
+}
+
+func (c contractChecker) checkStruct(path string, typ reflect.Type, s *schemaDoc) {
+	if c.sparse && len(s.Required) > 0 {
+		c.t.Errorf("%s: sparse schema must not have required fields, got %v", path, s.Required)
+	}
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		seen[name] = true
+
+		prop, ok := s.Properties[name]
+		if !ok {
+			c.t.Errorf("%s.%s: field is missing in spec", path, name)
+			continue
+		}
+		if !c.sparse && !strings.Contains(opts, "omitempty") && !required[name] {
+			c.t.Errorf("%s.%s: field is always present but not required in spec", path, name)
+		}
+		c.check(path+"."+name, field.Type, prop)
+	}
+
+	var extra []string
+	for name := range s.Properties {
+		if !seen[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		c.t.Errorf("%s.%s: property is in spec but not in dto", path, name)
+	}
+}
+
+func (c contractChecker) expectType(path string, s *schemaDoc, want string) {
+	if s.Type != want {
+		c.t.Errorf("%s: expected spec type %s, got %q", path, want, s.Type)
+	}
+}
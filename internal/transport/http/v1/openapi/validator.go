@@ -0,0 +1,334 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
+
+	"github.com/gorilla/mux"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const specURL = "openapi.json"
+
+const (
+	codeRequestInvalid  = "request_invalid"
+	codeResponseInvalid = "response_contract_violation"
+)
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	integer  bool
+	schema   *jsonschema.Schema
+}
+
+type operation struct {
+	params []parameter
+	// responses: статус ("200", "default") -> media type -> схема тела.
+	responses map[string]map[string]*jsonschema.Schema
+}
+
+// Validator проверяет запросы и ответы /api/v1 по OpenAPI документу. Схемы компилируются
+// из Spec() один раз при создании.
+type Validator struct {
+	operations map[string]*operation
+}
+
+func NewValidator() (*Validator, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(spec))
+	if err != nil {
+		return nil, fmt.Errorf("parse openapi spec: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.AssertFormat()
+	if err = compiler.AddResource(specURL, doc); err != nil {
+		return nil, fmt.Errorf("add openapi resource: %w", err)
+	}
+
+	var parsed struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err = json.Unmarshal(spec, &parsed); err != nil {
+		return nil, fmt.Errorf("decode openapi paths: %w", err)
+	}
+
+	v := &Validator{operations: make(map[string]*operation)}
+	for path, item := range parsed.Paths {
+		for method, raw := range item {
+			if !isHTTPMethod(method) {
+				continue
+			}
+			op, err := compileOperation(compiler, []string{"paths", path, method}, raw)
+			if err != nil {
+				return nil, fmt.Errorf("compile %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			v.operations[operationKey(strings.ToUpper(method), path)] = op
+		}
+	}
+
+	return v, nil
+}
+
+func compileOperation(compiler *jsonschema.Compiler, ptr []string, raw json.RawMessage) (*operation, error) {
+	var spec struct {
+		Parameters []struct {
+			Name     string `json:"name"`
+			In       string `json:"in"`
+			Required bool   `json:"required"`
+			Schema   struct {
+				Type string `json:"type"`
+			} `json:"schema"`
+		} `json:"parameters"`
+		Responses map[string]struct {
+			Content map[string]json.RawMessage `json:"content"`
+		} `json:"responses"`
+	}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, err
+	}
+
+	op := &operation{responses: make(map[string]map[string]*jsonschema.Schema)}
+	for i, p := range spec.Parameters {
+		sch, err := compiler.Compile(schemaLocation(append(ptr, "parameters", strconv.Itoa(i), "schema")...))
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		op.params = append(op.params, parameter{
+			name:     p.Name,
+			in:       p.In,
+			required: p.Required,
+			integer:  p.Schema.Type == "integer",
+			schema:   sch,
+		})
+	}
+
+	for code, resp := range spec.Responses {
+		op.responses[code] = make(map[string]*jsonschema.Schema)
+		for mediaType := range resp.Content {
+			sch, err := compiler.Compile(schemaLocation(append(ptr, "responses", code, "content", mediaType, "schema")...))
+			if err != nil {
+				return nil, fmt.Errorf("response %s %s: %w", code, mediaType, err)
+			}
+			op.responses[code][mediaType] = sch
+		}
+	}
+
+	return op, nil
+}
+
+// Middleware отклоняет запросы, не соответствующие спецификации (400), и подменяет
+// несоответствующие ей ответы на 500. Предназначен для dev-режима: ответ буферизуется целиком.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := v.lookup(r)
+		if op == nil {
+			slog.Warn("openapi: route is not documented", slog.String("method", r.Method), slog.String("path", r.URL.Path))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := op.validateRequest(r); err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if err := op.validateResponse(rec); err != nil {
+			slog.Error(
+				"openapi: response does not match spec",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Any("error", err),
+			)
+			problem.Write(w, r, &service.Error{
+				Kind:    service.KindInternal,
+				Code:    codeResponseInvalid,
+				Message: "response does not match openapi spec",
+				Err:     err,
+			})
+			return
+		}
+
+		rec.flush(w)
+	})
+}
+
+func (v *Validator) lookup(r *http.Request) *operation {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return nil
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return nil
+	}
+
+	return v.operations[operationKey(r.Method, tmpl)]
+}
+
+func (op *operation) validateRequest(r *http.Request) error {
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	for _, p := range op.params {
+		var (
+			value   string
+			present bool
+		)
+		switch p.in {
+		case "path":
+			value, present = vars[p.name]
+		case "query":
+			present = query.Has(p.name)
+			value = query.Get(p.name)
+		case "header":
+			value = r.Header.Get(p.name)
+			present = value != ""
+		default:
+			continue
+		}
+
+		if !present {
+			if p.required {
+				return invalidParameter(p, "required")
+			}
+			continue
+		}
+
+		var instance any = value
+		if p.integer {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return invalidParameter(p, "must be an integer")
+			}
+			instance = json.Number(strconv.FormatInt(n, 10))
+		}
+		if err := p.schema.Validate(instance); err != nil {
+			reason := "does not match schema"
+			var vErr *jsonschema.ValidationError
+			if errors.As(err, &vErr) {
+				reason = firstCause(vErr)
+			}
+			return invalidParameter(p, reason)
+		}
+	}
+
+	return nil
+}
+
+func invalidParameter(p parameter, reason string) error {
+	return service.InvalidInput(
+		codeRequestInvalid,
+		fmt.Sprintf("%s parameter %q is invalid", p.in, p.name),
+		service.FieldError{Field: p.name, Reason: reason},
+	)
+}
+
+func firstCause(err *jsonschema.ValidationError) string {
+	for len(err.Causes) > 0 {
+		err = err.Causes[0]
+	}
+	return err.ErrorKind.LocalizedString(message.NewPrinter(language.English))
+}
+
+func (op *operation) validateResponse(rec *bufferedResponse) error {
+	byType, ok := op.responses[strconv.Itoa(rec.status)]
+	if !ok {
+		byType, ok = op.responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("status %d is not documented", rec.status)
+	}
+	if len(byType) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(rec.header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("invalid content type: %w", err)
+	}
+	sch, ok := byType[mediaType]
+	if !ok {
+		return fmt.Errorf("content type %s is not documented for status %d", mediaType, rec.status)
+	}
+
+	var instance any = rec.body.String()
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		instance, err = jsonschema.UnmarshalJSON(bytes.NewReader(rec.body.Bytes()))
+		if err != nil {
+			return fmt.Errorf("decode body: %w", err)
+		}
+	}
+
+	return sch.Validate(instance)
+}
+
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(statusCode int) {
+	b.status = statusCode
+}
+
+func (b *bufferedResponse) flush(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(b.status)
+	_, _ = w.Write(b.body.Bytes())
+}
+
+func operationKey(method, path string) string {
+	return method + " " + path
+}
+
+func isHTTPMethod(s string) bool {
+	switch strings.ToUpper(s) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// schemaLocation строит ссылку вида openapi.json#/paths/~1api~1v1~1order~1{order_id}/get/...
+func schemaLocation(tokens ...string) string {
+	var sb strings.Builder
+	sb.WriteString(specURL + "#")
+	for _, tok := range tokens {
+		tok = strings.ReplaceAll(tok, "~", "~0")
+		tok = strings.ReplaceAll(tok, "/", "~1")
+		sb.WriteString("/" + url.PathEscape(tok))
+	}
+	return sb.String()
+}
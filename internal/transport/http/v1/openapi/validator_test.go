@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web_demoservice/internal/domain"
+	"web_demoservice/internal/service"
+	"web_demoservice/internal/transport/http/problem"
+	"web_demoservice/internal/transport/http/v1/dto"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func newTestRouter(t *testing.T, h http.HandlerFunc) *mux.Router {
+	t.Helper()
+
+	v, err := NewValidator()
+	if err != nil {
+		t.Fatalf("new validator: %v", err)
+	}
+
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api/v1").Subrouter()
+	api.Use(v.Middleware)
+	api.HandleFunc("/order/{order_id}", h).Methods(http.MethodGet)
+	return r
+}
+
+func TestValidator_RejectsInvalidPathParam(t *testing.T) {
+	r := newTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("handler should not be called")
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/order/not-a-uuid", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Fatalf("expected content type %s, got %s", problem.ContentType, ct)
+	}
+}
+
+func TestValidator_RejectsResponseNotMatchingSpec(t *testing.T) {
+	r := newTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"order_uid": 42})
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/order/"+uuid.NewString(), nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+}
+
+func TestValidator_PassesOrderDTO(t *testing.T) {
+	id := uuid.New()
+	r := newTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		order := domain.OrderWithInformation{}
+		order.ID = id
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(dto.MapToOrderDTO(&order))
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/order/"+id.String(), nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
+
+func TestValidator_PassesSparseOrder(t *testing.T) {
+	id := uuid.New()
+	r := newTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		order := domain.OrderWithInformation{}
+		order.ID = id
+		sel, err := dto.ParseSelection(r.URL.Query().Get("fields"), r.URL.Query().Get("include"))
+		if err != nil {
+			t.Fatalf("parse selection: %v", err)
+		}
+		body, err := sel.Apply(dto.MapToOrderDTO(&order))
+		if err != nil {
+			t.Fatalf("apply selection: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	})
+
+	rec := httptest.NewRecorder()
+	target := "/api/v1/order/" + id.String() + "?fields=order_uid,payment.amount,items.name&include=delivery"
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
+
+func TestValidator_PassesDocumentedResponse(t *testing.T) {
+	r := newTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, service.NotFound(service.CodeOrderNotFound, "order not found", nil))
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/order/"+uuid.NewString(), nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"net/http"
+	"web_demoservice/internal/transport/http/v1/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterAuditRoutes(r *mux.Router, handler *handlers.AuditHandler) {
+	r.HandleFunc("/admin/audit/events", handler.ListEvents).Methods(http.MethodGet)
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"net/http"
+	"web_demoservice/internal/transport/http/v1/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterCacheRoutes(r *mux.Router, handler *handlers.CacheHandler) {
+	cr := r.PathPrefix("/admin/cache/entries").Subrouter()
+	cr.HandleFunc("", handler.ListEntries).Methods(http.MethodGet)
+	cr.HandleFunc("", handler.Flush).Methods(http.MethodDelete)
+	cr.HandleFunc("/{order_id}", handler.GetEntry).Methods(http.MethodGet)
+	cr.HandleFunc("/{order_id}", handler.EvictEntry).Methods(http.MethodDelete)
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"net/http"
+	"web_demoservice/internal/transport/http/v1/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterCustomerRoutes(r *mux.Router, handler *handlers.CustomerHandler) {
+	cr := r.PathPrefix("/admin/customers").Subrouter()
+	cr.HandleFunc("/{customer_id}/export", handler.ExportCustomer).Methods(http.MethodGet)
+	cr.HandleFunc("/{customer_id}/erase", handler.EraseCustomer).Methods(http.MethodPost)
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"web_demoservice/internal/transport/http/v1/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+// RegisterDebugRoutes: r — корень admin listener'а, pprof.Index разбирает путь от /debug/pprof/.
+func RegisterDebugRoutes(r *mux.Router, handler *handlers.DebugHandler) {
+	r.HandleFunc("/debug/status", handler.Status).Methods(http.MethodGet)
+	r.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	r.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	r.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	r.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	r.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
+}
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"net/http"
+	"web_demoservice/internal/transport/http/v1/openapi"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterOpenAPIRoutes(r *mux.Router) {
+	r.Handle("/openapi.json", openapi.Handler()).Methods(http.MethodGet)
+}
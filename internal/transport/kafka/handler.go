@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 	"web_demoservice/internal/domain"
 	"web_demoservice/internal/infra/kafka"
+	"web_demoservice/internal/logging"
+	"web_demoservice/internal/retry"
+	"web_demoservice/internal/service"
 	"web_demoservice/internal/telemetry"
 
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -24,96 +28,176 @@ type DLQProducer interface {
 	Publish(ctx context.Context, record *kgo.Record, cause error) error
 }
 
+type AuditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
 type OrderHandler struct {
 	consumer *kafka.Consumer
 	dlq      DLQProducer
 	service  OrderService
+	audit    AuditRecorder
+	backoff  retry.Policy
 }
 
-func NewOrderHandler(consumer *kafka.Consumer, dlq DLQProducer, service OrderService) *OrderHandler {
+// NewOrderHandler: audit равен nil, если журнал аудита выключен. backoff задаёт паузы
+// между повторами записи, пока хранилище недоступно (Attempts не используется).
+func NewOrderHandler(consumer *kafka.Consumer, dlq DLQProducer, service OrderService, audit AuditRecorder, backoff retry.Policy) *OrderHandler {
 	return &OrderHandler{
 		consumer: consumer,
 		dlq:      dlq,
 		service:  service,
+		audit:    audit,
+		backoff:  backoff,
 	}
 }
 
+// Run читает записи, пока не отменён ctx. Отмена прекращает только получение новых
+// записей: уже полученные дообрабатываются и отмечаются к коммиту.
 func (h *OrderHandler) Run(ctx context.Context) {
+	handleCtx := context.WithoutCancel(ctx)
 	for {
 		if ctx.Err() != nil {
 			return
 		}
 		fetches := h.consumer.Fetch(ctx)
-		fetches.EachRecord(func(record *kgo.Record) {
-			carrier := propagation.HeaderCarrier{}
-			for _, header := range record.Headers {
-				carrier.Set(header.Key, string(header.Value))
-			}
-			parentCtx := otel.GetTextMapPropagator().Extract(ctx, carrier)
-			recordCtx, span := otel.Tracer("kafka").Start(parentCtx, "kafka.consume")
-			span.SetAttributes(
-				attribute.String("messaging.system", "kafka"),
-				attribute.String("messaging.destination", record.Topic),
-				attribute.Int("messaging.kafka.partition", int(record.Partition)),
-				attribute.Int64("messaging.kafka.offset", record.Offset),
-			)
-			defer span.End()
-
-			var kafkaDTO OrderKafkaDTO
-			if err := json.Unmarshal(record.Value, &kafkaDTO); err != nil {
-				wrappedErr := fmt.Errorf("unmarshal kafka record: %w", err)
-				slog.Error("failed to unmarshal kafka record", slog.Any("error", wrappedErr))
-				span.RecordError(wrappedErr)
-				span.SetStatus(codes.Error, wrappedErr.Error())
-				telemetry.IncKafkaResult("invalid")
-				h.sendToDLQ(recordCtx, record, wrappedErr)
+		for iter := fetches.RecordIter(); !iter.Done(); {
+			record := iter.Next()
+			if !h.process(ctx, handleCtx, record) {
 				return
 			}
+			h.consumer.MarkDone(record)
+			telemetry.ObserveKafkaProcessed(time.Since(record.Timestamp))
+		}
+	}
+}
 
-			if err := kafkaDTO.Validate(); err != nil {
-				wrappedErr := fmt.Errorf("validate kafka dto: %w", err)
-				slog.Error("failed to validate kafka dto", slog.Any("error", wrappedErr))
-				span.RecordError(wrappedErr)
-				span.SetStatus(codes.Error, wrappedErr.Error())
-				telemetry.IncKafkaResult("invalid")
-				h.sendToDLQ(recordCtx, record, wrappedErr)
-				return
-			}
+// process обрабатывает запись. Пока хранилище недоступно, чтение топика ставится на паузу,
+// а запись повторяется с backoff — в DLQ она не уходит. false — ctx отменён во время
+// паузы: запись не отмечена и после рестарта будет прочитана снова.
+func (h *OrderHandler) process(ctx, handleCtx context.Context, record *kgo.Record) bool {
+	paused := false
+	defer func() {
+		if paused {
+			h.consumer.Resume()
+		}
+	}()
 
-			order, err := kafkaDTO.ToDomain()
-			if err != nil {
-				wrappedErr := fmt.Errorf("map kafka dto to domain: %w", err)
-				slog.Error("failed to map kafka dto to domain", slog.Any("error", wrappedErr))
-				span.RecordError(wrappedErr)
-				span.SetStatus(codes.Error, wrappedErr.Error())
-				telemetry.IncKafkaResult("invalid")
-				h.sendToDLQ(recordCtx, record, wrappedErr)
-				return
+	for attempt := 0; ; attempt++ {
+		err := h.handle(handleCtx, record)
+		if err == nil {
+			if paused {
+				slog.InfoContext(handleCtx, "storage is available again, resuming consumption", slog.Int("attempts", attempt+1))
 			}
+			return true
+		}
 
-			if err := h.service.CreateOrder(recordCtx, order); err != nil {
-				wrappedErr := fmt.Errorf("save order from kafka: %w", err)
-				slog.Error("failed to save order from kafka", slog.Any("error", wrappedErr))
-				span.RecordError(wrappedErr)
-				span.SetStatus(codes.Error, wrappedErr.Error())
-				telemetry.IncKafkaResult("error")
-				h.sendToDLQ(recordCtx, record, wrappedErr)
-				return
-			}
+		if !paused {
+			paused = true
+			h.consumer.Pause()
+			slog.WarnContext(handleCtx, "storage is unavailable, pausing consumption", slog.Any("error", err))
+		}
+		timer := time.NewTimer(h.backoff.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+// handle обрабатывает одну запись; длительность каждого этапа (decode, validate, map,
+// persist, dlq) попадает в kafka_stage_duration_seconds. Ошибка возвращается только при
+// недоступном хранилище — запись нужно повторить; остальные ошибки уходят в DLQ.
+func (h *OrderHandler) handle(ctx context.Context, record *kgo.Record) error {
+	carrier := propagation.HeaderCarrier{}
+	for _, header := range record.Headers {
+		carrier.Set(header.Key, string(header.Value))
+	}
+	parentCtx := otel.GetTextMapPropagator().Extract(ctx, carrier)
+	recordCtx, span := otel.Tracer("kafka").Start(parentCtx, "kafka.consume")
+	span.SetAttributes(
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.destination", record.Topic),
+		attribute.Int("messaging.kafka.partition", int(record.Partition)),
+		attribute.Int64("messaging.kafka.offset", record.Offset),
+	)
+	defer span.End()
+
+	fail := func(result, msg string, err error) {
+		slog.ErrorContext(recordCtx, msg, slog.Any("error", err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		telemetry.IncKafkaResult(result)
+		start := time.Now()
+		h.sendToDLQ(recordCtx, record, err)
+		telemetry.ObserveKafkaStage("dlq", time.Since(start))
+	}
+
+	start := time.Now()
+	var kafkaDTO OrderKafkaDTO
+	err := json.Unmarshal(record.Value, &kafkaDTO)
+	telemetry.ObserveKafkaStage("decode", time.Since(start))
+	if err != nil {
+		fail("invalid", "failed to unmarshal kafka record", fmt.Errorf("unmarshal kafka record: %w", err))
+		return nil
+	}
+
+	start = time.Now()
+	err = kafkaDTO.Validate()
+	telemetry.ObserveKafkaStage("validate", time.Since(start))
+	if err != nil {
+		fail("invalid", "failed to validate kafka dto", fmt.Errorf("validate kafka dto: %w", err))
+		return nil
+	}
+
+	start = time.Now()
+	order, err := kafkaDTO.ToDomain()
+	telemetry.ObserveKafkaStage("map", time.Since(start))
+	if err != nil {
+		fail("invalid", "failed to map kafka dto to domain", fmt.Errorf("map kafka dto to domain: %w", err))
+		return nil
+	}
+
+	recordCtx = logging.WithOrderID(recordCtx, order.ID.String())
+	start = time.Now()
+	err = h.service.CreateOrder(recordCtx, order)
+	telemetry.ObserveKafkaStage("persist", time.Since(start))
+	if service.KindOf(err) == service.KindUnavailable {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		telemetry.IncKafkaResult("retry")
+		return err
+	}
+	if err != nil {
+		fail("error", "failed to save order from kafka", fmt.Errorf("save order from kafka: %w", err))
+		return nil
+	}
 
-			telemetry.IncKafkaResult("ok")
+	telemetry.IncKafkaResult("ok")
+	if h.audit != nil {
+		h.audit.Record(recordCtx, domain.AuditEvent{
+			Actor:   "kafka:" + record.Topic,
+			Action:  domain.AuditActionOrderCreate,
+			OrderID: &order.ID,
+			Details: map[string]any{
+				"partition": record.Partition,
+				"offset":    record.Offset,
+			},
 		})
 	}
+	return nil
 }
 
 func (h *OrderHandler) sendToDLQ(ctx context.Context, record *kgo.Record, cause error) {
 	if h.dlq == nil {
-		slog.Error("dlq producer is nil", slog.Any("error", cause))
+		slog.ErrorContext(ctx, "dlq producer is nil", slog.Any("error", cause))
 		return
 	}
 
 	if err := h.dlq.Publish(ctx, record, cause); err != nil {
 		telemetry.IncKafkaDLQPublishFailure()
-		slog.Error("failed to publish to dlq", slog.Any("error", err))
+		slog.ErrorContext(ctx, "failed to publish to dlq", slog.Any("error", err))
 	}
 }
@@ -0,0 +1,21 @@
+-- Откат удалил бы шифротекст без восстановления открытых колонок: ключи только в keyfile,
+-- расшифровать строки в SQL нельзя. Поэтому миграция откатывается, только пока зашифрованных
+-- строк нет; после включения шифрования она односторонняя.
+do $$
+begin
+    if exists (select 1 from orders.delivery where pii_ciphertext is not null) then
+        raise exception 'orders.delivery has encrypted rows; rolling back 00004 would destroy delivery PII';
+    end if;
+end
+$$;
+
+-- NOT NULL на name/phone/address/email не возвращается: у анонимизированных строк эти колонки пустые.
+drop index if exists orders.idx_delivery_pii_key_id;
+drop index if exists orders.idx_delivery_phone_bidx;
+drop index if exists orders.idx_delivery_email_bidx;
+alter table orders.delivery
+    drop column if exists phone_bidx,
+    drop column if exists email_bidx,
+    drop column if exists pii_key_id,
+    drop column if exists pii_data_key,
+    drop column if exists pii_ciphertext;
@@ -0,0 +1,17 @@
+-- Шифрование PII доставки: name, phone, address, email хранятся в pii_ciphertext
+-- (AES-GCM ключом данных), ключ данных — в pii_data_key, завёрнутый мастер-ключом pii_key_id.
+-- Открытые колонки остаются для строк, записанных до включения шифрования.
+ALTER TABLE orders.delivery
+    ALTER COLUMN name DROP NOT NULL,
+    ALTER COLUMN phone DROP NOT NULL,
+    ALTER COLUMN address DROP NOT NULL,
+    ALTER COLUMN email DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS pii_ciphertext BYTEA,
+    ADD COLUMN IF NOT EXISTS pii_data_key BYTEA,
+    ADD COLUMN IF NOT EXISTS pii_key_id VARCHAR(125),
+    ADD COLUMN IF NOT EXISTS email_bidx BYTEA,
+    ADD COLUMN IF NOT EXISTS phone_bidx BYTEA;
+
+CREATE INDEX IF NOT EXISTS idx_delivery_email_bidx ON orders.delivery(email_bidx);
+CREATE INDEX IF NOT EXISTS idx_delivery_phone_bidx ON orders.delivery(phone_bidx);
+CREATE INDEX IF NOT EXISTS idx_delivery_pii_key_id ON orders.delivery(pii_key_id);
@@ -0,0 +1,4 @@
+drop trigger if exists trg_audit_events_append_only on audit.events;
+drop function if exists audit.forbid_change();
+drop table if exists audit.events;
+drop schema if exists audit;
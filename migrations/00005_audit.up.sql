@@ -0,0 +1,29 @@
+-- Журнал аудита: кто и когда читал или менял заказ. Только INSERT, изменение и удаление
+-- записей запрещены триггером.
+CREATE SCHEMA IF NOT EXISTS audit;
+
+CREATE TABLE IF NOT EXISTS audit.events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    actor VARCHAR(255) NOT NULL,
+    auth_method VARCHAR(32),
+    action VARCHAR(64) NOT NULL,
+    order_id UUID,
+    fields TEXT[],
+    trace_id VARCHAR(32),
+    details JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_order_id ON audit.events(order_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit.events(actor, id);
+
+CREATE OR REPLACE FUNCTION audit.forbid_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit.events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_events_append_only ON audit.events;
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON audit.events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit.forbid_change();
@@ -0,0 +1,2 @@
+alter table orders.delivery
+    drop column if exists anonymized_at;
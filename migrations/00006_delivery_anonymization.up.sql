@@ -0,0 +1,4 @@
+-- Анонимизация доставки по запросу на удаление (GDPR): PII обнуляются, строка и финансовые
+-- данные заказа остаются. anonymized_at отмечает такие строки, ротация ключей их пропускает.
+ALTER TABLE orders.delivery
+    ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;